@@ -1,5 +1,7 @@
 use std::process;
 
+use crate::preview::ColorMode;
+
 pub enum Mode {
     Stdio,
     Oneshoot,
@@ -7,8 +9,15 @@ pub enum Mode {
     KakouneRc,
     ListGrammars,
     ListGrammarsPlain,
+    HasGrammar(String),
     ListThemes,
     ListThemesPlain,
+    Preview {
+        path: String,
+        lang: Option<String>,
+        theme: Option<String>,
+        colors: ColorMode,
+    },
 }
 
 pub fn print_help() {
@@ -34,15 +43,24 @@ pub fn print_help() {
     println!("  init                    Print Kakoune integration script");
     println!("  list-grammars           List available grammar files");
     println!("  list-themes             List available theme files");
+    println!("  preview <FILE>          Print FILE highlighted with ANSI colors");
     println!();
     println!("GRAMMAR/THEME LIST OPTIONS:");
     println!("  --plain                 Output plain list (one per line, for fzf)");
     println!();
+    println!("PREVIEW OPTIONS:");
+    println!("      --lang <LANG>       Grammar to use (default: from file extension)");
+    println!("      --theme <THEME>     Theme to use (default: from config)");
+    println!("      --colors <MODE>     truecolor, 256 or 16 (default: detected from");
+    println!("                          COLORTERM, TERM and TERM_PROGRAM)");
+    println!("                          NO_COLOR disables colors entirely");
+    println!();
     println!("EXAMPLES:");
     println!("  giallo-kak init                    # Print Kakoune script");
     println!("  giallo-kak list-grammars           # List grammars with descriptions");
     println!("  giallo-kak list-grammars --plain   # List grammar names only");
     println!("  giallo-kak list-themes --plain     # List theme names only");
+    println!("  giallo-kak preview src/main.rs | less -R");
     println!();
     println!("For more information: https://github.com/yukai/giallo.kak");
 }
@@ -56,6 +74,12 @@ pub fn parse_args() -> (Mode, bool, bool) {
     let mut list_grammars = false;
     let mut list_themes = false;
     let mut plain_output = false;
+    let mut has_grammar: Option<String> = None;
+    let mut preview = false;
+    let mut preview_path: Option<String> = None;
+    let mut preview_lang: Option<String> = None;
+    let mut preview_theme: Option<String> = None;
+    let mut colors: Option<ColorMode> = None;
 
     let mut args = std::env::args().skip(1);
     while let Some(arg) = args.next() {
@@ -75,6 +99,15 @@ pub fn parse_args() -> (Mode, bool, bool) {
             "list-grammars" | "--list-grammars" => list_grammars = true,
             "list-themes" | "--list-themes" => list_themes = true,
             "--plain" => plain_output = true,
+            // Not in --help: exits 0 when the registry has the grammar, for
+            // scripts and tests that can't rely on the curated list-grammars
+            "has-grammar" => {
+                let Some(id) = args.next() else {
+                    eprintln!("has-grammar: missing grammar id");
+                    process::exit(2);
+                };
+                has_grammar = Some(id);
+            }
             "--fifo" => {
                 if let Some(path) = args.next() {
                     fifo_req = Some(path);
@@ -85,11 +118,40 @@ pub fn parse_args() -> (Mode, bool, bool) {
                     fifo_resp = Some(path);
                 }
             }
+            "preview" => preview = true,
+            "--lang" => preview_lang = args.next(),
+            "--theme" => preview_theme = args.next(),
+            "--colors" => {
+                let value = args.next().unwrap_or_default();
+                let Some(mode) = ColorMode::parse(&value) else {
+                    eprintln!("invalid --colors value: {value} (expected truecolor, 256 or 16)");
+                    process::exit(2);
+                };
+                colors = Some(mode);
+            }
+            // The file may come before or after the preview options
+            _ if preview && preview_path.is_none() && !arg.starts_with('-') => {
+                preview_path = Some(arg);
+            }
             _ => {}
         }
     }
 
-    let mode = if list_grammars {
+    if preview && preview_path.is_none() {
+        eprintln!("preview: missing file argument");
+        process::exit(2);
+    }
+
+    let mode = if let Some(path) = preview_path {
+        Mode::Preview {
+            path,
+            lang: preview_lang,
+            theme: preview_theme,
+            colors: colors.unwrap_or_else(ColorMode::detect),
+        }
+    } else if let Some(id) = has_grammar {
+        Mode::HasGrammar(id)
+    } else if list_grammars {
         if plain_output {
             Mode::ListGrammarsPlain
         } else {
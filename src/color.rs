@@ -0,0 +1,127 @@
+/// xterm defaults for the 16 base ANSI slots, in SGR order
+const ANSI_16: [(u8, u8, u8); 16] = [
+    (0x00, 0x00, 0x00),
+    (0x80, 0x00, 0x00),
+    (0x00, 0x80, 0x00),
+    (0x80, 0x80, 0x00),
+    (0x00, 0x00, 0x80),
+    (0x80, 0x00, 0x80),
+    (0x00, 0x80, 0x80),
+    (0xc0, 0xc0, 0xc0),
+    (0x80, 0x80, 0x80),
+    (0xff, 0x00, 0x00),
+    (0x00, 0xff, 0x00),
+    (0xff, 0xff, 0x00),
+    (0x00, 0x00, 0xff),
+    (0xff, 0x00, 0xff),
+    (0x00, 0xff, 0xff),
+    (0xff, 0xff, 0xff),
+];
+
+const CUBE_LEVELS: [u8; 6] = [0, 95, 135, 175, 215, 255];
+
+pub fn normalize_hex(hex: &str) -> String {
+    if hex.len() == 9 {
+        hex[..7].to_string()
+    } else {
+        hex.to_string()
+    }
+}
+
+pub fn strip_hash(hex: &str) -> &str {
+    if hex.starts_with('#') {
+        &hex[1..]
+    } else {
+        hex
+    }
+}
+
+pub fn parse_hex(hex: &str) -> Option<(u8, u8, u8)> {
+    let hex = strip_hash(hex);
+    if hex.len() < 6 {
+        return None;
+    }
+    let r = u8::from_str_radix(hex.get(0..2)?, 16).ok()?;
+    let g = u8::from_str_radix(hex.get(2..4)?, 16).ok()?;
+    let b = u8::from_str_radix(hex.get(4..6)?, 16).ok()?;
+    Some((r, g, b))
+}
+
+/// Black or white, whichever reads better on `rgb`
+pub fn readable_foreground((r, g, b): (u8, u8, u8)) -> &'static str {
+    let brightness = 0.299 * r as f64 + 0.587 * g as f64 + 0.114 * b as f64;
+    if brightness > 150.0 {
+        "000000"
+    } else {
+        "ffffff"
+    }
+}
+
+/// sRGB to CIE L*a*b* (D65), used so nearest-color searches follow perceived
+/// distance instead of raw RGB distance
+fn to_lab((r, g, b): (u8, u8, u8)) -> (f64, f64, f64) {
+    fn linear(c: u8) -> f64 {
+        let c = c as f64 / 255.0;
+        if c <= 0.04045 {
+            c / 12.92
+        } else {
+            ((c + 0.055) / 1.055).powf(2.4)
+        }
+    }
+    fn f(t: f64) -> f64 {
+        if t > 0.008856 {
+            t.cbrt()
+        } else {
+            7.787 * t + 16.0 / 116.0
+        }
+    }
+
+    let (r, g, b) = (linear(r), linear(g), linear(b));
+    let x = (0.4124 * r + 0.3576 * g + 0.1805 * b) / 0.95047;
+    let y = 0.2126 * r + 0.7152 * g + 0.0722 * b;
+    let z = (0.0193 * r + 0.1192 * g + 0.9505 * b) / 1.08883;
+    let (fx, fy, fz) = (f(x), f(y), f(z));
+    (116.0 * fy - 16.0, 500.0 * (fx - fy), 200.0 * (fy - fz))
+}
+
+fn lab_distance(a: (f64, f64, f64), b: (f64, f64, f64)) -> f64 {
+    (a.0 - b.0).powi(2) + (a.1 - b.1).powi(2) + (a.2 - b.2).powi(2)
+}
+
+fn palette_256(index: u8) -> (u8, u8, u8) {
+    match index {
+        0..=15 => ANSI_16[index as usize],
+        16..=231 => {
+            let i = index - 16;
+            (
+                CUBE_LEVELS[(i / 36) as usize],
+                CUBE_LEVELS[(i / 6 % 6) as usize],
+                CUBE_LEVELS[(i % 6) as usize],
+            )
+        }
+        _ => {
+            let level = 8 + (index - 232) * 10;
+            (level, level, level)
+        }
+    }
+}
+
+fn nearest(rgb: (u8, u8, u8), candidates: impl Iterator<Item = (u8, (u8, u8, u8))>) -> u8 {
+    let target = to_lab(rgb);
+    candidates
+        .map(|(index, candidate)| (index, lab_distance(target, to_lab(candidate))))
+        .min_by(|a, b| a.1.total_cmp(&b.1))
+        .map(|(index, _)| index)
+        .unwrap_or(0)
+}
+
+/// Nearest xterm-256 index. The 16 base slots are skipped since terminals
+/// remap them freely; only the 6x6x6 cube and grayscale ramp are stable.
+pub fn quantize_to_256(rgb: (u8, u8, u8)) -> u8 {
+    nearest(rgb, (16..=255u8).map(|i| (i, palette_256(i))))
+}
+
+/// Nearest of the 8 normal + 8 bright ANSI colors, as a 0-15 slot
+pub fn quantize_to_16(rgb: (u8, u8, u8)) -> u8 {
+    nearest(rgb, (0..16u8).map(|i| (i, ANSI_16[i as usize])))
+}
@@ -14,7 +14,10 @@ pub fn list_grammars(registry: &Registry, config: &Config, plain: bool) {
         "python",
         "javascript",
         "typescript",
+        "tsx",
         "json",
+        "jsonc",
+        "json5",
         "yaml",
         "toml",
         "markdown",
@@ -40,7 +43,10 @@ pub fn list_grammars(registry: &Registry, config: &Config, plain: bool) {
         "make",
         "cmake",
         "ini",
+        "csv",
+        "tsv",
         "diff",
+        "log",
         "git-commit",
         "git-rebase",
         "graphql",
@@ -52,6 +58,7 @@ pub fn list_grammars(registry: &Registry, config: &Config, plain: bool) {
         "erlang",
         "elixir",
         "haskell",
+        "nix",
         "ocaml",
         "fsharp",
         "r",
@@ -5,6 +5,9 @@ use std::path::PathBuf;
 
 const DEFAULT_THEME: &str = "catppuccin-frappe";
 
+/// Grammars whose `#rrggbb` literals are drawn on their own color
+const COLOR_SWATCH_GRAMMARS: &[&str] = &["css", "scss", "less", "toml"];
+
 #[derive(Clone, Debug, Default, Deserialize)]
 pub struct Config {
     pub theme: Option<String>,
@@ -16,6 +19,10 @@ pub struct Config {
     pub grammars_path: Option<String>,
     #[serde(default)]
     pub themes_path: Option<String>,
+    #[serde(default)]
+    pub no_italics: bool,
+    #[serde(default)]
+    pub no_color_swatches: bool,
 }
 
 impl Config {
@@ -47,6 +54,11 @@ impl Config {
             .unwrap_or_else(|| lang.to_string())
     }
 
+    /// Whether hex color literals get swatch faces for the resolved `lang`
+    pub fn color_swatches(&self, lang: &str) -> bool {
+        !self.no_color_swatches && COLOR_SWATCH_GRAMMARS.contains(&lang)
+    }
+
     pub fn resolve_theme<'a>(&'a self, theme: &'a str) -> &'a str {
         if theme.is_empty() {
             self.theme.as_deref().unwrap_or(DEFAULT_THEME)
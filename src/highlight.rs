@@ -91,7 +91,11 @@ pub fn highlight_and_send(
         }
     };
 
-    let (faces, ranges) = build_kakoune_commands(&highlighted);
+    let (faces, ranges) = build_kakoune_commands(
+        &highlighted,
+        config.no_italics,
+        config.color_swatches(&resolved_lang),
+    );
     log::debug!(
         "highlight: built {} faces and {} ranges",
         faces.len(),
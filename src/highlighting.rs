@@ -1,6 +1,8 @@
 use giallo::ThemeVariant;
 use std::collections::HashMap;
 
+use crate::color::{normalize_hex, parse_hex, readable_foreground, strip_hash};
+
 #[derive(Clone, Debug, PartialEq, Eq, Hash)]
 pub struct StyleKey {
     pub fg: String,
@@ -17,39 +19,27 @@ pub struct FaceDef {
     pub spec: String,
 }
 
-pub fn normalize_hex(hex: &str) -> String {
-    if hex.len() == 9 {
-        hex[..7].to_string()
-    } else {
-        hex.to_string()
-    }
-}
-
-pub fn style_key(style: &giallo::Style) -> StyleKey {
+pub fn style_key(style: &giallo::Style, no_italics: bool) -> StyleKey {
     StyleKey {
         fg: normalize_hex(&style.foreground.as_hex()),
         bg: normalize_hex(&style.background.as_hex()),
         bold: style.font_style.contains(giallo::FontStyle::BOLD),
-        italic: style.font_style.contains(giallo::FontStyle::ITALIC),
+        italic: !no_italics && style.font_style.contains(giallo::FontStyle::ITALIC),
         underline: style.font_style.contains(giallo::FontStyle::UNDERLINE),
         strike: style.font_style.contains(giallo::FontStyle::STRIKETHROUGH),
     }
 }
 
-pub fn strip_hash(hex: &str) -> &str {
-    if hex.starts_with('#') {
-        &hex[1..]
-    } else {
-        hex
-    }
-}
-
-pub fn style_to_face_spec(style: &giallo::Style, default_bg: Option<&str>) -> String {
+pub fn style_to_face_spec(
+    style: &giallo::Style,
+    default_bg: Option<&str>,
+    no_italics: bool,
+) -> String {
     let mut attrs = String::new();
     if style.font_style.contains(giallo::FontStyle::BOLD) {
         attrs.push('b');
     }
-    if style.font_style.contains(giallo::FontStyle::ITALIC) {
+    if !no_italics && style.font_style.contains(giallo::FontStyle::ITALIC) {
         attrs.push('i');
     }
     if style.font_style.contains(giallo::FontStyle::UNDERLINE) {
@@ -81,7 +71,39 @@ pub fn style_to_face_spec(style: &giallo::Style, default_bg: Option<&str>) -> St
     }
 }
 
-pub fn build_kakoune_commands(highlighted: &giallo::HighlightedCode<'_>) -> (Vec<FaceDef>, String) {
+/// Byte offsets (start, end exclusive) and the six color digits of every
+/// `#rrggbb` or `#rrggbbaa` literal in `line`. Shorter forms are left out,
+/// since `#add` or `#bad` are just as likely to be CSS id selectors.
+fn find_hex_colors(line: &str) -> Vec<(usize, usize, &str)> {
+    let bytes = line.as_bytes();
+    let mut found = Vec::new();
+    let mut i = 0;
+    while i < bytes.len() {
+        if bytes[i] != b'#' {
+            i += 1;
+            continue;
+        }
+        let digits = bytes[i + 1..]
+            .iter()
+            .take_while(|b| b.is_ascii_hexdigit())
+            .count();
+        let end = i + 1 + digits;
+        let at_boundary = bytes.get(end).map_or(true, |b| {
+            !(b.is_ascii_alphanumeric() || *b == b'_' || *b == b'-')
+        });
+        if (digits == 6 || digits == 8) && at_boundary {
+            found.push((i, end, &line[i + 1..i + 7]));
+        }
+        i = end;
+    }
+    found
+}
+
+pub fn build_kakoune_commands(
+    highlighted: &giallo::HighlightedCode<'_>,
+    no_italics: bool,
+    color_swatches: bool,
+) -> (Vec<FaceDef>, String) {
     let theme = match highlighted.theme {
         ThemeVariant::Single(theme) => theme,
         ThemeVariant::Dual { light, .. } => light,
@@ -92,6 +114,7 @@ pub fn build_kakoune_commands(highlighted: &giallo::HighlightedCode<'_>) -> (Vec
 
     let mut faces: Vec<FaceDef> = Vec::new();
     let mut face_map: HashMap<StyleKey, String> = HashMap::new();
+    let mut swatch_map: HashMap<String, String> = HashMap::new();
     let mut face_counter = 0usize;
 
     let mut ranges: Vec<String> = Vec::new();
@@ -115,13 +138,13 @@ pub fn build_kakoune_commands(highlighted: &giallo::HighlightedCode<'_>) -> (Vec
             let face_name = if style == default_style {
                 "default".to_string()
             } else {
-                let key = style_key(&style);
+                let key = style_key(&style, no_italics);
                 if let Some(name) = face_map.get(&key) {
                     name.clone()
                 } else {
                     face_counter += 1;
                     let name = format!("giallo_{face_counter:04}");
-                    let spec = style_to_face_spec(&style, Some(&default_bg));
+                    let spec = style_to_face_spec(&style, Some(&default_bg), no_italics);
                     faces.push(FaceDef {
                         name: name.clone(),
                         spec,
@@ -137,6 +160,36 @@ pub fn build_kakoune_commands(highlighted: &giallo::HighlightedCode<'_>) -> (Vec
 
             ranges.push(format!("{line}.{col_start},{line}.{col_end}|{face_name}"));
         }
+
+        // Swatches come after the line's token ranges so Kakoune draws them
+        // on top. The line is rebuilt from its tokens because grammars often
+        // split a literal into `#` and its digits.
+        if color_swatches {
+            let text: String = line_tokens.iter().map(|token| token.text).collect();
+            let line = line_idx + 1;
+            for (start, end_excl, digits) in find_hex_colors(&text) {
+                let hex = digits.to_ascii_lowercase();
+                let Some(rgb) = parse_hex(&hex) else {
+                    continue;
+                };
+                let face_name = if let Some(name) = swatch_map.get(&hex) {
+                    name.clone()
+                } else {
+                    face_counter += 1;
+                    let name = format!("giallo_{face_counter:04}");
+                    faces.push(FaceDef {
+                        name: name.clone(),
+                        spec: format!("rgb:{},rgb:{hex}", readable_foreground(rgb)),
+                    });
+                    swatch_map.insert(hex, name.clone());
+                    name
+                };
+                ranges.push(format!(
+                    "{line}.{},{line}.{end_excl}|{face_name}",
+                    start + 1
+                ));
+            }
+        }
     }
 
     let ranges_str = if ranges.is_empty() {
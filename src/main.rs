@@ -7,12 +7,14 @@ use giallo::Registry;
 use log;
 
 mod cli;
+mod color;
 mod commands;
 mod config;
 mod fifo;
 mod highlight;
 mod highlighting;
 mod kakoune;
+mod preview;
 mod registry_loader;
 mod server;
 mod server_resources;
@@ -20,6 +22,7 @@ mod server_resources;
 use cli::{parse_args, Mode};
 use commands::{list_grammars, list_themes};
 use config::Config;
+use preview::preview_file;
 use registry_loader::{load_custom_grammars, load_custom_themes};
 use server::run_server;
 use server_resources::ServerResources;
@@ -171,12 +174,34 @@ fn main() {
         Mode::ListGrammarsPlain => {
             list_grammars(&registry, &config, true);
         }
+        Mode::HasGrammar(id) => {
+            process::exit(if registry.contains_grammar(&id) { 0 } else { 1 });
+        }
         Mode::ListThemes => {
             list_themes(&registry, &config, false);
         }
         Mode::ListThemesPlain => {
             list_themes(&registry, &config, true);
         }
+        Mode::Preview {
+            path,
+            lang,
+            theme,
+            colors,
+        } => {
+            if let Err(err) = preview_file(
+                &registry,
+                &config,
+                &path,
+                lang.as_deref(),
+                theme.as_deref(),
+                colors,
+            ) {
+                log::error!("preview failed: {path}: {err}");
+                eprintln!("preview failed: {path}: {err}");
+                process::exit(1);
+            }
+        }
         Mode::KakouneRc => unreachable!(),
     }
 
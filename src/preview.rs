@@ -0,0 +1,229 @@
+use std::fs;
+use std::io::{self, Write};
+use std::path::Path;
+
+use giallo::{HighlightOptions, Registry, ThemeVariant, PLAIN_GRAMMAR_NAME};
+use log;
+
+use crate::color::{normalize_hex, parse_hex, quantize_to_16, quantize_to_256, strip_hash};
+use crate::config::Config;
+
+#[derive(Clone, Copy, Debug, PartialEq, Eq)]
+pub enum ColorMode {
+    TrueColor,
+    Ansi256,
+    Ansi16,
+}
+
+impl ColorMode {
+    pub fn parse(value: &str) -> Option<Self> {
+        match value {
+            "truecolor" | "24bit" => Some(ColorMode::TrueColor),
+            "256" => Some(ColorMode::Ansi256),
+            "16" => Some(ColorMode::Ansi16),
+            _ => None,
+        }
+    }
+
+    /// Guess what the terminal supports from COLORTERM, TERM and TERM_PROGRAM,
+    /// falling back to 256 colors when nothing is conclusive
+    pub fn detect() -> Self {
+        let var = |name: &str| std::env::var(name).unwrap_or_default().to_lowercase();
+        let colorterm = var("COLORTERM");
+        let term = var("TERM");
+        let term_program = var("TERM_PROGRAM");
+
+        if colorterm == "truecolor" || colorterm == "24bit" || term.ends_with("-direct") {
+            return ColorMode::TrueColor;
+        }
+        if matches!(
+            term_program.as_str(),
+            "iterm.app" | "wezterm" | "vscode" | "ghostty"
+        ) {
+            return ColorMode::TrueColor;
+        }
+        if term.contains("256color") {
+            return ColorMode::Ansi256;
+        }
+        if matches!(
+            term.as_str(),
+            "linux" | "ansi" | "vt100" | "vt102" | "vt220" | "cons25"
+        ) {
+            return ColorMode::Ansi16;
+        }
+        ColorMode::Ansi256
+    }
+}
+
+fn color_sgr(rgb: (u8, u8, u8), mode: ColorMode, background: bool) -> String {
+    let (r, g, b) = rgb;
+    match mode {
+        ColorMode::TrueColor => {
+            let layer = if background { 48 } else { 38 };
+            format!("{layer};2;{r};{g};{b}")
+        }
+        ColorMode::Ansi256 => {
+            let layer = if background { 48 } else { 38 };
+            format!("{layer};5;{}", quantize_to_256(rgb))
+        }
+        ColorMode::Ansi16 => {
+            let slot = quantize_to_16(rgb);
+            let base = match (background, slot < 8) {
+                (false, true) => 30,
+                (false, false) => 90 - 8,
+                (true, true) => 40,
+                (true, false) => 100 - 8,
+            };
+            format!("{}", base + slot as u32)
+        }
+    }
+}
+
+fn style_sgr(style: &giallo::Style, default_bg: &str, mode: ColorMode, no_italics: bool) -> String {
+    let mut codes: Vec<String> = Vec::new();
+    if style.font_style.contains(giallo::FontStyle::BOLD) {
+        codes.push(String::from("1"));
+    }
+    if !no_italics && style.font_style.contains(giallo::FontStyle::ITALIC) {
+        codes.push(String::from("3"));
+    }
+    if style.font_style.contains(giallo::FontStyle::UNDERLINE) {
+        codes.push(String::from("4"));
+    }
+    if style.font_style.contains(giallo::FontStyle::STRIKETHROUGH) {
+        codes.push(String::from("9"));
+    }
+
+    if let Some(fg) = parse_hex(&style.foreground.as_hex()) {
+        codes.push(color_sgr(fg, mode, false));
+    }
+
+    // Like the Kakoune faces, leave the theme background to the terminal
+    let bg_hex = normalize_hex(&style.background.as_hex());
+    if strip_hash(&bg_hex) != strip_hash(default_bg) {
+        if let Some(bg) = parse_hex(&bg_hex) {
+            codes.push(color_sgr(bg, mode, true));
+        }
+    }
+
+    format!("\x1b[{}m", codes.join(";"))
+}
+
+/// Guess a giallo language from a file name or extension
+pub fn lang_from_path(path: &str) -> String {
+    let path = Path::new(path);
+    let name = path.file_name().and_then(|n| n.to_str()).unwrap_or("");
+    match name {
+        "Dockerfile" | "Containerfile" => return String::from("docker"),
+        "Makefile" | "GNUmakefile" => return String::from("make"),
+        "COMMIT_EDITMSG" => return String::from("git-commit"),
+        ".gitconfig" => return String::from("ini"),
+        _ => {}
+    }
+
+    let ext = path.extension().and_then(|e| e.to_str()).unwrap_or("");
+    let lang = match ext {
+        "rs" => "rust",
+        "js" | "mjs" | "cjs" => "javascript",
+        "ts" | "mts" | "cts" => "typescript",
+        "py" => "python",
+        "go" => "go",
+        "rb" => "ruby",
+        "sh" | "bash" => "shellscript",
+        "md" => "markdown",
+        "yml" => "yaml",
+        "h" => "c",
+        "hpp" | "cc" => "cpp",
+        "hs" => "haskell",
+        "tf" | "tfvars" => "terraform",
+        "kt" | "kts" => "kotlin",
+        "ex" | "exs" => "elixir",
+        "erl" => "erlang",
+        "clj" => "clojure",
+        "ml" => "ocaml",
+        "fs" => "fsharp",
+        "jl" => "julia",
+        "pl" | "pm" => "perl",
+        "mk" => "make",
+        _ => ext,
+    };
+    lang.to_string()
+}
+
+pub fn preview_file(
+    registry: &Registry,
+    config: &Config,
+    path: &str,
+    lang: Option<&str>,
+    theme: Option<&str>,
+    mode: ColorMode,
+) -> io::Result<()> {
+    let text = fs::read_to_string(path)?;
+    let stdout = io::stdout();
+    let mut out = stdout.lock();
+
+    if std::env::var("NO_COLOR").is_ok_and(|v| !v.is_empty()) {
+        log::debug!("preview: NO_COLOR set, printing plain text");
+        out.write_all(text.as_bytes())?;
+        return out.flush();
+    }
+
+    let lang = lang.map_or_else(|| lang_from_path(path), |l| l.to_string());
+    let resolved_lang = config.resolve_lang(&lang);
+    let resolved_theme = config.resolve_theme(theme.unwrap_or(""));
+
+    log::debug!(
+        "preview: path={} lang={} (resolved={}) theme={} mode={:?}",
+        path,
+        lang,
+        resolved_lang,
+        resolved_theme,
+        mode
+    );
+
+    let options = HighlightOptions::new(&resolved_lang, ThemeVariant::Single(resolved_theme));
+    let highlighted = match registry.highlight(&text, &options) {
+        Ok(h) => h,
+        Err(err) => {
+            log::warn!("preview: failed with lang={resolved_lang}, trying plain: {err}");
+            eprintln!("preview: no grammar for `{resolved_lang}`, showing plain text (use --lang)");
+            let fallback =
+                HighlightOptions::new(PLAIN_GRAMMAR_NAME, ThemeVariant::Single(resolved_theme));
+            registry
+                .highlight(&text, &fallback)
+                .map_err(|err| io::Error::new(io::ErrorKind::Other, err.to_string()))?
+        }
+    };
+
+    let theme = match highlighted.theme {
+        ThemeVariant::Single(theme) => theme,
+        ThemeVariant::Dual { light, .. } => light,
+    };
+    let default_style = theme.default_style;
+    let default_bg = normalize_hex(&default_style.background.as_hex());
+
+    let mut rendered = String::with_capacity(text.len() * 2);
+    for (line_idx, line_tokens) in highlighted.tokens.iter().enumerate() {
+        if line_idx > 0 {
+            rendered.push('\n');
+        }
+        for token in line_tokens {
+            if token.text.is_empty() {
+                continue;
+            }
+            let ThemeVariant::Single(style) = token.style else {
+                rendered.push_str(token.text);
+                continue;
+            };
+            rendered.push_str(&style_sgr(&style, &default_bg, mode, config.no_italics));
+            rendered.push_str(token.text);
+            rendered.push_str("\x1b[0m");
+        }
+    }
+    if text.ends_with('\n') && !rendered.ends_with('\n') {
+        rendered.push('\n');
+    }
+
+    out.write_all(rendered.as_bytes())?;
+    out.flush()
+}
@@ -136,7 +136,11 @@ pub fn run_server<R: BufRead, W: Write>(
                 }
             };
 
-            let (faces, ranges) = crate::highlighting::build_kakoune_commands(&highlighted);
+            let (faces, ranges) = crate::highlighting::build_kakoune_commands(
+                &highlighted,
+                config.no_italics,
+                config.color_swatches(&resolved_lang),
+            );
             let commands = crate::highlighting::build_commands(&faces, &ranges);
 
             if let Err(err) = writeln!(writer, "{}", commands) {
@@ -19,17 +19,21 @@ fn make_temp_dir(prefix: &str) -> PathBuf {
     dir
 }
 
-fn write_config(config_dir: &Path, theme: &str) {
+fn write_config(config_dir: &Path, theme: &str, extra: &str) {
     let cfg_dir = config_dir.join("giallo.kak");
     fs::create_dir_all(&cfg_dir).expect("failed to create config dir");
     let config_path = cfg_dir.join("config.toml");
-    let contents = format!("theme = \"{}\"\n", theme);
+    let contents = format!("theme = \"{}\"\n{}", theme, extra);
     fs::write(&config_path, contents).expect("failed to write config");
 }
 
 fn run_oneshot_highlight(lang: &str, theme: &str, code: &str) -> String {
+    run_oneshot_highlight_with_config(lang, theme, "", code)
+}
+
+fn run_oneshot_highlight_with_config(lang: &str, theme: &str, extra: &str, code: &str) -> String {
     let config_home = make_temp_dir("giallo-kak-test-config");
-    write_config(&config_home, theme);
+    write_config(&config_home, theme, extra);
 
     let payload = code.as_bytes();
     let header = format!("H {} {} {}\n", lang, theme, payload.len());
@@ -103,6 +107,20 @@ fn assert_valid_highlighting(output: &str, fixture_name: &str) {
     }
 }
 
+/// Attribute suffixes (`+bius`) of every face definition in the output
+fn face_attributes(output: &str) -> Vec<String> {
+    output
+        .lines()
+        .filter(|line| line.starts_with("set-face global"))
+        .map(|line| {
+            let spec = line.trim_end().trim_end_matches('}');
+            spec.rsplit_once('+')
+                .map(|(_, attrs)| attrs.to_string())
+                .unwrap_or_default()
+        })
+        .collect()
+}
+
 fn count_highlights(output: &str) -> usize {
     // Count the number of highlight ranges
     let ranges_line = output
@@ -201,6 +219,104 @@ fn fixture_go_sample() {
     );
 }
 
+/// Fixtures checked for basic highlighting: file name, language, and the
+/// number of ranges the file must exceed. Exact spans are locked by
+/// `snapshot_tests.rs`; behavior specific to a language is tested in
+/// `string_keyword_highlighting.rs`.
+const SAMPLES: &[(&str, &str, usize)] = &[
+    ("go_generics_sample.go", "go", 50),
+    ("ts_sample.ts", "typescript", 50),
+    ("tsx_sample.tsx", "tsx", 50),
+    ("markdown_sample.md", "markdown", 50),
+    ("c_sample.c", "c", 50),
+    ("yaml_sample.yaml", "yaml", 50),
+    ("sql_sample.sql", "sql", 50),
+    ("toml_sample.toml", "toml", 50),
+    ("shell_sample.sh", "shellscript", 50),
+    ("html_sample.html", "html", 50),
+    ("css_sample.css", "css", 50),
+    ("lua_sample.lua", "lua", 50),
+    ("gitconfig_sample", "ini", 20),
+    ("nix_sample.nix", "nix", 50),
+    ("jsonc_sample.jsonc", "jsonc", 50),
+    ("json5_sample.json5", "json5", 50),
+    ("proto_sample.proto", "proto", 50),
+    ("calls_sample.ts", "typescript", 50),
+    ("csv_sample.csv", "csv", 20),
+    ("tsv_sample.tsv", "tsv", 20),
+    ("haskell_sample.hs", "haskell", 50),
+    ("commit_sample.txt", "git-commit", 10),
+    ("log_sample.log", "log", 20),
+];
+
+#[test]
+fn fixture_samples() {
+    for &(file_name, lang, min_ranges) in SAMPLES {
+        let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+            .join("tests")
+            .join("fixtures")
+            .join(file_name);
+
+        let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+        let output = run_oneshot_highlight(lang, "catppuccin-frappe", &code);
+
+        assert_valid_highlighting(&output, file_name);
+
+        let count = count_highlights(&output);
+        assert!(
+            count > min_ranges,
+            "{} should have substantial highlighting, got {} ranges",
+            file_name,
+            count
+        );
+    }
+}
+
+#[test]
+fn fixture_diff_sample() {
+    let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join("diff_sample.diff");
+
+    let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+    let output = run_oneshot_highlight("diff", "catppuccin-frappe", &code);
+
+    assert_valid_highlighting(&output, "diff_sample.diff");
+
+    // Added and removed lines must not share a face
+    let ranges_line = output
+        .lines()
+        .find(|line| line.starts_with("set-option buffer giallo_hl_ranges"))
+        .expect("should have ranges line");
+    let face_of_line = |line: usize| {
+        let prefix = format!("{}.1,", line);
+        ranges_line
+            .split_whitespace()
+            .find(|range| range.starts_with(&prefix))
+            .and_then(|range| range.split_once('|'))
+            .map(|(_, face)| face.to_string())
+    };
+    let deleted = face_of_line(9).expect("removed line should be highlighted");
+    let added = face_of_line(10).expect("added line should be highlighted");
+    assert_ne!(
+        deleted, added,
+        "added and removed lines should use different faces"
+    );
+
+    // The ---/+++ file headers are not removed/added content
+    let from_file = face_of_line(3).expect("--- header should be highlighted");
+    let to_file = face_of_line(4).expect("+++ header should be highlighted");
+    assert_ne!(
+        from_file, deleted,
+        "--- header should not use the removed-line face"
+    );
+    assert_ne!(
+        to_file, added,
+        "+++ header should not use the added-line face"
+    );
+}
+
 #[test]
 fn fixture_rust_with_different_themes() {
     let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
@@ -359,3 +475,315 @@ const u = `outer "double" 'single' outer`;"#;
     let count = count_highlights(&output);
     assert!(count > 10, "nested strings should be highlighted");
 }
+
+/// Write a custom theme that styles keywords, strings and comments with
+/// combined font styles, returning the config line that loads it
+fn write_attribute_theme(themes_dir: &Path) -> String {
+    fs::create_dir_all(themes_dir).expect("failed to create themes dir");
+    let theme_path = themes_dir.join("giallo-attributes.json");
+    let contents = r##"{
+  "name": "giallo-attributes",
+  "type": "dark",
+  "colors": {
+    "editor.background": "#1e1e2e",
+    "editor.foreground": "#cdd6f4"
+  },
+  "tokenColors": [
+    { "scope": "keyword", "settings": { "foreground": "#cba6f7", "fontStyle": "bold italic underline" } },
+    { "scope": "string", "settings": { "foreground": "#a6e3a1", "fontStyle": "bold italic" } },
+    { "scope": "comment", "settings": { "foreground": "#6c7086", "fontStyle": "italic" } }
+  ]
+}"##;
+    fs::write(&theme_path, contents).expect("failed to write theme");
+    format!("themes_path = \"{}\"\n", themes_dir.display())
+}
+
+#[test]
+fn fixture_face_attribute_suffixes() {
+    let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join("rust_sample.rs");
+
+    let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+
+    for theme in ["catppuccin-frappe", "kanagawa-wave", "tokyo-night"] {
+        let output = run_oneshot_highlight("rust", theme, &code);
+        for attrs in face_attributes(&output) {
+            // Combined attributes are always emitted in b, i, u, s order
+            let canonical: String = "bius".chars().filter(|c| attrs.contains(*c)).collect();
+            assert_eq!(
+                attrs, canonical,
+                "{}: unexpected attribute suffix +{}",
+                theme, attrs
+            );
+        }
+    }
+
+    let themes_dir = make_temp_dir("giallo-kak-test-themes");
+    let extra = write_attribute_theme(&themes_dir);
+    let output = run_oneshot_highlight_with_config("rust", "giallo-attributes", &extra, &code);
+    let _ = fs::remove_dir_all(&themes_dir);
+
+    let attrs = face_attributes(&output);
+    for expected in ["biu", "bi", "i"] {
+        assert!(
+            attrs.iter().any(|a| a == expected),
+            "custom theme should produce a +{} face, got {:?}",
+            expected,
+            attrs
+        );
+    }
+}
+
+#[test]
+fn fixture_no_italics_config() {
+    let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join("rust_sample.rs");
+
+    let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+
+    // Without the option, the fixture does produce italic faces
+    let themes = ["catppuccin-frappe", "kanagawa-wave", "tokyo-night"];
+    let italic_themes: Vec<&str> = themes
+        .iter()
+        .copied()
+        .filter(|theme| {
+            let output = run_oneshot_highlight("rust", theme, &code);
+            face_attributes(&output).iter().any(|a| a.contains('i'))
+        })
+        .collect();
+    assert!(
+        !italic_themes.is_empty(),
+        "at least one theme should produce italics for rust_sample.rs"
+    );
+
+    for theme in italic_themes {
+        let output = run_oneshot_highlight_with_config("rust", theme, "no_italics = true\n", &code);
+        assert_valid_highlighting(
+            &output,
+            &format!("rust_sample.rs with {} (no italics)", theme),
+        );
+
+        for attrs in face_attributes(&output) {
+            assert!(
+                !attrs.contains('i'),
+                "{}: italic attribute should be stripped, got +{}",
+                theme,
+                attrs
+            );
+        }
+    }
+
+    // Other attributes of a combined style survive
+    let themes_dir = make_temp_dir("giallo-kak-test-themes");
+    let extra = write_attribute_theme(&themes_dir);
+    let output = run_oneshot_highlight_with_config(
+        "rust",
+        "giallo-attributes",
+        &format!("{extra}no_italics = true\n"),
+        &code,
+    );
+    let _ = fs::remove_dir_all(&themes_dir);
+
+    let attrs = face_attributes(&output);
+    assert!(
+        attrs.iter().all(|a| !a.contains('i')),
+        "italic attribute should be stripped, got {:?}",
+        attrs
+    );
+    assert!(
+        attrs.iter().any(|a| a == "bu"),
+        "bold and underline should be kept, got {:?}",
+        attrs
+    );
+}
+
+#[test]
+fn fixture_faces_leave_theme_background_to_terminal() {
+    let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join("markdown_sample.md");
+
+    let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+    let output = run_oneshot_highlight("markdown", "catppuccin-frappe", &code);
+
+    // catppuccin-frappe's editor background is #303446; faces on it must use
+    // `default` so a transparent terminal background shows through
+    for line in output.lines().filter(|l| l.starts_with("set-face global")) {
+        assert!(
+            !line.to_lowercase().contains(",rgb:303446"),
+            "face should not paint the theme background: {}",
+            line
+        );
+    }
+    assert!(
+        output.contains(",default"),
+        "faces should use the default background"
+    );
+}
+
+#[test]
+fn fixture_faces_defined_once_and_referenced() {
+    let fixtures = [
+        ("rust", "rust_sample.rs"),
+        ("javascript", "javascript_sample.js"),
+        ("python", "python_sample.py"),
+        ("go", "go_sample.go"),
+    ];
+
+    for (lang, name) in fixtures {
+        let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+            .join("tests")
+            .join("fixtures")
+            .join(name);
+
+        let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+        let output = run_oneshot_highlight(lang, "catppuccin-frappe", &code);
+
+        let mut defined: Vec<&str> = output
+            .lines()
+            .filter_map(|line| line.strip_prefix("set-face global "))
+            .filter_map(|rest| rest.split_whitespace().next())
+            .collect();
+        let total = defined.len();
+        defined.sort_unstable();
+        defined.dedup();
+        assert_eq!(
+            defined.len(),
+            total,
+            "{}: a face was defined more than once",
+            name
+        );
+
+        let ranges_line = output
+            .lines()
+            .find(|line| line.starts_with("set-option buffer giallo_hl_ranges"))
+            .expect("should have ranges line");
+        let used: Vec<&str> = ranges_line
+            .split_whitespace()
+            .skip(4)
+            .filter_map(|range| range.split_once('|'))
+            .map(|(_, face)| face)
+            .collect();
+
+        for face in &used {
+            assert!(
+                *face == "default" || defined.binary_search(face).is_ok(),
+                "{}: range uses undefined face {}",
+                name,
+                face
+            );
+        }
+        for face in &defined {
+            assert!(
+                used.contains(face),
+                "{}: face {} is defined but never used",
+                name,
+                face
+            );
+        }
+    }
+}
+
+/// Name of the face defined with exactly `spec`
+fn face_with_spec(output: &str, spec: &str) -> Option<String> {
+    let suffix = format!(" %{{{}}}", spec);
+    output
+        .lines()
+        .filter_map(|line| line.strip_prefix("set-face global "))
+        .find_map(|rest| rest.strip_suffix(suffix.as_str()))
+        .map(|name| name.to_string())
+}
+
+fn has_range(output: &str, range: &str) -> bool {
+    output
+        .lines()
+        .find(|line| line.starts_with("set-option buffer giallo_hl_ranges"))
+        .expect("should have ranges line")
+        .split_whitespace()
+        .any(|r| r == range)
+}
+
+#[test]
+fn fixture_color_swatches() {
+    let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join("css_sample.css");
+
+    let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+    let output = run_oneshot_highlight("css", "catppuccin-frappe", &code);
+    assert_valid_highlighting(&output, "css_sample.css");
+
+    // `--accent: #e5c890;` gets a dark foreground, `background-color: #303446;`
+    // a light one, each drawn on the literal's own color
+    for (spec, span) in [
+        ("rgb:000000,rgb:e5c890", "7.13,7.19"),
+        ("rgb:ffffff,rgb:303446", "15.21,15.27"),
+    ] {
+        let face = face_with_spec(&output, spec)
+            .unwrap_or_else(|| panic!("css_sample.css: no swatch face %{{{}}}", spec));
+        assert!(
+            has_range(&output, &format!("{}|{}", span, face)),
+            "css_sample.css: {} should use swatch face {}",
+            span,
+            face
+        );
+    }
+
+    // Only the three literals are swatched, not the `#main` id selector
+    let mut swatched: Vec<&str> = output
+        .lines()
+        .filter_map(|line| line.strip_prefix("set-face global "))
+        .filter_map(|rest| rest.split_once(" %{"))
+        .map(|(_, spec)| spec.trim_end_matches('}'))
+        .filter(|spec| spec.starts_with("rgb:000000,rgb:") || spec.starts_with("rgb:ffffff,rgb:"))
+        .map(|spec| &spec[spec.len() - 6..])
+        .collect();
+    swatched.sort_unstable();
+    assert_eq!(
+        swatched,
+        ["303446", "babbf1", "e5c890"],
+        "css_sample.css: unexpected swatch colors"
+    );
+
+    // Hex strings in TOML are swatched too
+    let output = run_oneshot_highlight("toml", "catppuccin-frappe", "accent = \"#e5c890\"\n");
+    let face = face_with_spec(&output, "rgb:000000,rgb:e5c890")
+        .expect("toml: hex string should get a swatch face");
+    assert!(
+        has_range(&output, &format!("1.11,1.17|{}", face)),
+        "toml: the swatch should cover `#e5c890` inside the string"
+    );
+}
+
+#[test]
+fn fixture_color_swatches_only_where_enabled() {
+    let code = "let accent = \"#e5c890\";\n";
+    let output = run_oneshot_highlight("rust", "catppuccin-frappe", code);
+    assert!(
+        face_with_spec(&output, "rgb:000000,rgb:e5c890").is_none(),
+        "rust: hex strings should not get swatches"
+    );
+
+    let fixture_path = Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join("css_sample.css");
+    let code = fs::read_to_string(&fixture_path).expect("failed to read fixture");
+    let output = run_oneshot_highlight_with_config(
+        "css",
+        "catppuccin-frappe",
+        "no_color_swatches = true\n",
+        &code,
+    );
+    assert_valid_highlighting(&output, "css_sample.css with no_color_swatches");
+    assert!(
+        face_with_spec(&output, "rgb:000000,rgb:e5c890").is_none(),
+        "no_color_swatches should turn swatches off"
+    );
+}
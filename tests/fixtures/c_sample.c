@@ -0,0 +1,73 @@
+// Test fixture for C syntax highlighting
+// Should test: preprocessor directives, macros, escapes, numeric literals, comments
+
+#include <stdio.h>
+#include <stdlib.h>
+#include "local_header.h"
+
+#define BUFFER_SIZE 256
+#define FLAG_MASK 0x1F
+#define PERMISSIONS 0755
+#define SQUARE(x) ((x) * (x))
+#define LOG_ERROR(fmt, ...) \
+    fprintf(stderr, "error: " fmt "\n", \
+            __VA_ARGS__)
+
+#ifdef DEBUG
+#  define TRACE(msg) puts(msg)
+#elif defined(VERBOSE)
+#  define TRACE(msg) fputs(msg, stderr)
+#else
+#  define TRACE(msg) ((void)0)
+#endif
+
+#if BUFFER_SIZE > 128
+static const char *size_label = "large";
+#endif
+
+/* Block comment
+ * spanning lines */
+typedef struct {
+    unsigned int flags;
+    long long total;
+    float ratio;
+} Stats;
+
+static int count_lines(const char *text) {
+    int lines = 0;
+    for (const char *p = text; *p != '\0'; ++p) {
+        if (*p == '\n') {
+            lines++;
+        }
+    }
+    return lines;
+}
+
+int main(int argc, char **argv) {
+    char buffer[BUFFER_SIZE];
+    char tab = '\t';
+    char quote = '\'';
+    const char *escaped = "tab:\t newline:\n quote:\" hex:\x41 octal:\101";
+    Stats stats = { .flags = FLAG_MASK, .total = 10LL, .ratio = 1.5e-3f };
+    unsigned long mask = 0xFFul;
+    int perms = PERMISSIONS;
+
+    if (argc < 2) {
+        LOG_ERROR("usage: %s <file>", argv[0]);
+        return EXIT_FAILURE;
+    }
+
+    snprintf(buffer, sizeof buffer, "%s%c%d", argv[1], tab, SQUARE(perms));
+    TRACE(buffer);
+
+    switch (stats.flags & mask) {
+    case 0:
+        break;
+    default:
+        goto done;
+    }
+
+done:
+    printf("%d %c %s %s\n", count_lines(escaped), quote, size_label, buffer);
+    return 0;
+}
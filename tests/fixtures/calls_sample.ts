@@ -0,0 +1,36 @@
+// Test fixture for function declaration vs call highlighting (TypeScript)
+// Should test: declarations, plain calls, method calls, constructor calls
+
+function formatName(first: string, last: string): string {
+  return `${first} ${last}`;
+}
+
+const shout = (text: string): string => text.toUpperCase();
+
+async function loadUser(id: number) {
+  const response = await fetch(`/users/${id}`);
+  return response.json();
+}
+
+class Greeter {
+  constructor(private readonly prefix: string) {}
+
+  greet(name: string): string {
+    return this.prefix + formatName(name, "Doe");
+  }
+
+  static create(): Greeter {
+    return new Greeter("Hello, ");
+  }
+}
+
+// Plain calls
+formatName("Ada", "Lovelace");
+shout("quiet");
+loadUser(42).then((user) => console.log(user));
+
+// Method calls: only the method name is a call, not the object
+const greeter = Greeter.create();
+greeter.greet("Grace");
+document.querySelector("#main")?.addEventListener("click", () => {});
+[1, 2, 3].map((n) => n * 2).filter(Boolean);
@@ -0,0 +1,88 @@
+// Test fixture for Go generics and struct tag syntax highlighting
+// Should test: type parameters, constraint interfaces, struct tags
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Constraint interfaces with approximation (~) and union (|) elements
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type Key interface {
+	~int | ~string
+}
+
+// Struct field tags are raw strings
+type User struct {
+	Name    string            `json:"name,omitempty"`
+	Email   string            `json:"email" db:"email_address"`
+	Age     int               `json:"age,string"`
+	Labels  map[string]string `json:"labels,omitempty" yaml:"labels"`
+	private bool
+}
+
+// Generic functions with multiple type parameters
+func Map[T, U any](items []T, fn func(T) U) []U {
+	out := make([]U, 0, len(items))
+	for _, item := range items {
+		out = append(out, fn(item))
+	}
+	return out
+}
+
+func Sum[N Number](values ...N) N {
+	var total N
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// Generic types and methods
+type Pair[K Key, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+func (p Pair[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", p.Key, p.Value)
+}
+
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(item T) {
+	s.items = append(s.items, item)
+}
+
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	item := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return item, true
+}
+
+func main() {
+	// Explicit and inferred instantiation
+	lengths := Map[string, int]([]string{"a", "bb"}, func(s string) int { return len(s) })
+	total := Sum(1.5, 2.5)
+	pair := Pair[string, int]{Key: "answer", Value: 42}
+
+	stack := &Stack[User]{}
+	stack.Push(User{Name: "Ada", Age: 36})
+
+	data, err := json.Marshal(pair)
+	if err != nil {
+		return
+	}
+	fmt.Println(lengths, total, string(data))
+}
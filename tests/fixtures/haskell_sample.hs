@@ -0,0 +1,69 @@
+-- Test fixture for Haskell syntax highlighting
+-- Should test: type signatures, operators, nested block comments, data declarations
+
+{- Block comment {- with a nested comment -} that
+   continues after the inner one closes -}
+{-# LANGUAGE LambdaCase #-}
+
+module Giallo.Sample
+  ( Color (..)
+  , Face (..)
+  , (<+>)
+  , render
+  ) where
+
+import qualified Data.Map.Strict as Map
+import Data.List (intercalate)
+
+-- | Data declarations with type constructors
+data Color = Rgb !Int !Int !Int | Named String
+  deriving (Show, Eq)
+
+data Face = Face
+  { faceFg    :: Maybe Color
+  , faceBg    :: Maybe Color
+  , faceAttrs :: [Char]
+  } deriving (Show)
+
+newtype Theme = Theme (Map.Map String Face)
+
+type Spans = [(Int, Int, Face)]
+
+class Renderable a where
+  render :: a -> String
+
+instance Renderable Color where
+  render (Rgb r g b) = "rgb:" ++ concatMap hex [r, g, b]
+  render (Named name) = name
+
+-- User-defined operators
+infixr 5 <+>
+(<+>) :: Face -> Face -> Face
+a <+> b = b { faceFg = faceFg b `orElse` faceFg a }
+
+orElse :: Maybe a -> Maybe a -> Maybe a
+orElse (Just x) _ = Just x
+orElse Nothing y = y
+
+hex :: Int -> String
+hex n
+  | n < 16 = '0' : showHex n
+  | otherwise = showHex n
+  where
+    showHex = \case
+      0 -> ""
+      k -> showHex (k `div` 16) ++ [digits !! (k `mod` 16)]
+    digits = "0123456789abcdef"
+
+spans :: Spans -> String
+spans = intercalate " " . map (\(s, e, f) -> show s ++ "," ++ show e ++ "|" ++ faceAttrs f)
+
+main :: IO ()
+main = do
+  let face = Face (Just (Rgb 229 200 144)) Nothing "b"
+      merged = face <+> face
+  putStrLn $ render (Rgb 0 255 16)
+  print (faceAttrs merged, 0x1F, 3.14e-2)
+  case faceFg merged of
+    Just c -> putStrLn (render c)
+    Nothing -> pure ()
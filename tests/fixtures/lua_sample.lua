@@ -0,0 +1,63 @@
+-- Test fixture for Lua syntax highlighting
+-- Should test: long bracket strings and comments, keywords, numbers
+
+--[[ Block comment
+spanning several lines ]]
+
+--[==[ Leveled block comment
+that contains ]] without ending
+]==]
+
+local M = {}
+
+-- Strings
+local single = 'single quoted'
+local double = "double \"escaped\" quotes"
+local long = [[A long string
+spanning lines]]
+local leveled = [==[A leveled string with ]] and ]=] inside]==]
+local after = "string after leveled brackets"
+
+-- Numbers
+local int, float, hex, exp = 42, 3.14, 0xFF, 1.5e-3
+
+-- Keywords
+function M.setup(opts)
+  opts = opts or {}
+  if opts.enabled == nil then
+    opts.enabled = true
+  elseif not opts.enabled then
+    return
+  else
+    opts.count = #opts
+  end
+
+  for i = 1, 10, 2 do
+    if i > 5 then break end
+  end
+
+  for key, value in pairs(opts) do
+    print(key, value)
+  end
+
+  while false do end
+  repeat
+    int = int - 1
+  until int <= 0
+
+  local ok = opts.enabled and true or false
+  return ok
+end
+
+local function helper(...)
+  local args = { ... }
+  goto done
+  ::done::
+  return select("#", ...), args
+end
+
+vim.keymap.set("n", "<leader>f", function()
+  print(helper(1, 2, 3))
+end, { desc = "Format" })
+
+return M
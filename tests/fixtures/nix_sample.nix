@@ -0,0 +1,50 @@
+# Test fixture for Nix syntax highlighting
+# Should test: antiquotation, indented strings and their escapes, keywords, comments
+
+/* Block comment
+   spanning lines */
+{ pkgs ? import <nixpkgs> { }, lib ? pkgs.lib, ... }:
+
+let
+  name = "giallo";
+  version = "0.3.0";
+  greeting = "Hello, ${name} ${version}!";
+  escaped = "not an \${antiquotation} and a \"quote\"";
+
+  # Indented strings: ''' is a literal '', ''${ is a literal ${
+  script = ''
+    echo "building ${name}"
+    echo '''quoted''' and ''${NOT_INTERPOLATED}
+    export PATH=${lib.makeBinPath [ pkgs.kakoune ]}:$PATH
+  '';
+
+  enabled = true;
+  count = 42;
+  ratio = 1.5;
+  src = ./src;
+in
+rec {
+  inherit name version;
+  inherit (pkgs) stdenv fetchFromGitHub;
+
+  meta = with lib; {
+    description = "Kakoune highlighting with ${name}";
+    license = licenses.mit;
+    platforms = platforms.unix;
+  };
+
+  package = pkgs.rustPlatform.buildRustPackage {
+    pname = name;
+    inherit version src;
+    cargoLock.lockFile = ./Cargo.lock;
+    doCheck = if enabled then count > 0 else false;
+    nativeBuildInputs = [ pkgs.pkg-config ] ++ lib.optional enabled pkgs.git;
+  };
+
+  shell = pkgs.mkShell {
+    packages = builtins.attrValues { inherit (pkgs) cargo rustc; };
+    shellHook = assert ratio > 1; ''
+      echo "entering ${name} shell"
+    '';
+  };
+}
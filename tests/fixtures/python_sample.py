@@ -72,3 +72,26 @@ global global_var
 nonlocal outer_var
 lambda x: x + 1
 yield 42
+
+# Interpolated f-strings
+width = 10
+label = "plain"
+padded = f"{label!r:>{width}}"
+nested = f"{'inner'} and {width * 2:08.3f}"
+after = "plain"
+
+# Docstrings and decorators
+class Account:
+    """Account with a computed balance.
+
+    The docstring spans multiple lines.
+    """
+
+    @property
+    def balance(self):
+        '''Single-quoted docstring.'''
+        return self._balance
+
+# Keyword-only arguments
+def connect(host, *, port=5432, timeout=None, **options):
+    return host, port, timeout, options
@@ -48,3 +48,33 @@ fn main() {
         Ok(String::from("data"))
     }
 }
+
+// Attributes and lifetimes
+#[derive(Debug, Clone)]
+struct Wrapper<'a> {
+    name: &'a str,
+    initial: char,
+}
+
+impl<'a> Wrapper<'a> {
+    fn first_word(&self) -> &'a str {
+        let quote = '\'';
+        let letter = 'a';
+        self.name.split(quote).next().unwrap_or(if letter == self.initial { "a" } else { "" })
+    }
+}
+
+// Declarative macros
+macro_rules! square {
+    ($x:expr) => {
+        $x * $x
+    };
+}
+
+// Raw strings and the ? operator
+fn parse_config(input: &str) -> Result<u32, std::num::ParseIntError> {
+    let template = r#"{"key": "value"}"#;
+    let nested = r##"contains "# inside"##;
+    let value = input.trim().parse::<u32>()?;
+    Ok(square!(value) + (template.len() + nested.len()) as u32)
+}
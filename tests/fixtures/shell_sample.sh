@@ -0,0 +1,53 @@
+#!/usr/bin/env bash
+# Test fixture for shell script syntax highlighting
+# Should test: variable expansion, quoting, command substitution, heredocs, comments
+
+set -euo pipefail
+
+name="giallo"
+greeting="Hello, $name and ${name}!"
+literal='No $expansion or ${here} in single quotes'
+fallback="${THEME:-catppuccin-frappe}"
+length=${#name}
+upper="${name^^}"
+today=$(date +%Y-%m-%d)
+legacy=`uname -s`
+count=$((length * 2 + 1))
+
+echo "$greeting" # comment after a command
+echo "items: ${#BASH_ARGV[@]} args: $# pid: $$ status: $?"
+
+if [[ -n "$name" && $count -gt 3 ]]; then
+    printf '%s\n' "long name"
+elif [ "$name" = "kak" ]; then
+    echo "short"
+else
+    echo 'other'
+fi
+
+for file in ./*.sh; do
+    case "$file" in
+        *test*) echo "test: $file" ;;
+        *) continue ;;
+    esac
+done
+
+greet() {
+    local who="${1:-world}"
+    echo "hi $who"
+    return 0
+}
+
+cat <<EOF
+Expanded: $name $(echo inline)
+EOF
+
+cat <<-'EOF'
+	Not expanded: $name $(echo inline)
+	EOF
+
+while read -r line; do
+    echo "$line"
+done < <(ls -1)
+
+export PATH="$HOME/.cargo/bin:$PATH"
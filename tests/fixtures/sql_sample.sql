@@ -0,0 +1,38 @@
+-- Test fixture for SQL syntax highlighting
+-- Should test: mixed-case keywords, string escapes, comments, numeric and NULL literals
+
+/* Block comment
+   spanning lines */
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    email TEXT UNIQUE,
+    score NUMERIC(5, 2) DEFAULT 0.0,
+    deleted_at TIMESTAMP NULL
+);
+
+INSERT INTO users (id, name, email, score)
+VALUES (1, 'O''Brien', 'ob@example.com', 98.5),
+       (2, 'It''s -- not a comment', NULL, -12);
+
+SELECT u.id, u.name, COUNT(*) AS total
+from users u
+Where u.score >= 50
+  AND u.deleted_at IS NULL
+  and u.email LIKE '%@example.com'
+GROUP BY u.id, u.name
+HAVING count(*) > 1
+ORDER BY total DESC
+LIMIT 10 OFFSET 20;
+
+-- A column named like a keyword, qualified by its table
+SELECT t.select, t."from" FROM options t;
+
+UPDATE users SET score = score * 1.1 WHERE id IN (SELECT id FROM users WHERE score < 1e2);
+delete from users where deleted_at is not null;
+
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY name ORDER BY score DESC) AS rn
+    FROM users
+)
+SELECT * FROM ranked WHERE rn = 1;
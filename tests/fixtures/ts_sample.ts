@@ -0,0 +1,60 @@
+// Test fixture for TypeScript syntax highlighting
+// Should test: template literals, type annotations, generics, comments
+
+// Template literals
+const user = "Ada";
+const greeting = `Hello, ${user}!`;
+const nested = `outer ${`inner ${user.toUpperCase()}`} outer`;
+const withString = `value: ${user === "Ada" ? `yes` : "no"}`;
+const markup = "<div>not a tag</div>";
+const after = "plain";
+
+// Type annotations
+let count: number = 0;
+let names: string[] = ["a", "b"];
+let maybe: string | undefined;
+
+// Generics
+interface Box<T> {
+    value: T;
+}
+
+function wrap<T extends object>(value: T): Box<T> {
+    return { value };
+}
+
+const boxes: Array<Box<number>> = [wrap({ n: 1 }) as unknown as Box<number>];
+const lookup = new Map<string, Array<number>>();
+
+// Keywords - types
+type Id = string | number;
+enum Direction {
+    Up = "UP",
+    Down = "DOWN",
+}
+
+abstract class Shape {
+    protected constructor(public readonly name: string) {}
+    abstract area(): number;
+}
+
+class Square extends Shape implements Box<number> {
+    value = 2;
+
+    constructor() {
+        super("square");
+    }
+
+    area(): number {
+        return this.value ** 2;
+    }
+}
+
+// Keywords - async
+async function load<T>(url: string): Promise<T> {
+    const response = await fetch(url);
+    return (await response.json()) as T;
+}
+
+export { Square, load };
+export type { Id };
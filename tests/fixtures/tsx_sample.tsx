@@ -0,0 +1,35 @@
+// Test fixture for TSX syntax highlighting
+// Should test: JSX tags, attributes, embedded expressions, generics
+
+import { useState } from "react";
+
+interface Props {
+    title: string;
+    items: Array<string>;
+}
+
+export function List({ title, items }: Props) {
+    const [open, setOpen] = useState<boolean>(false);
+    const label = `${items.length} ${items.length === 1 ? "item" : `items`}`;
+
+    return (
+        <section className="list" data-open={open}>
+            <h1 title="a </h1> inside a string">{title}</h1>
+            <button onClick={() => setOpen(!open)} disabled={items.length === 0}>
+                {open ? "Hide" : "Show"} {label}
+            </button>
+            {open && (
+                <ul>
+                    {items.map((item) => (
+                        <li key={item}>{`- ${item}`}</li>
+                    ))}
+                </ul>
+            )}
+            <Footer<string> value="done" />
+        </section>
+    );
+}
+
+function Footer<T>({ value }: { value: T }) {
+    return <footer>{String(value)}</footer>;
+}
@@ -0,0 +1,337 @@
+use std::fs;
+use std::path::{Path, PathBuf};
+use std::process::Command;
+
+fn make_temp_dir(prefix: &str) -> PathBuf {
+    let mut dir = std::env::temp_dir();
+    let unique = format!(
+        "{}-{}-{}",
+        prefix,
+        std::process::id(),
+        std::time::SystemTime::now()
+            .duration_since(std::time::UNIX_EPOCH)
+            .unwrap_or_default()
+            .as_nanos()
+    );
+    dir.push(unique);
+    fs::create_dir_all(&dir).expect("failed to create temp dir");
+    dir
+}
+
+fn fixture_path(name: &str) -> PathBuf {
+    Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+        .join(name)
+}
+
+fn run_preview(args: &[&str], no_color: bool) -> String {
+    if no_color {
+        run_preview_in_terminal(args, &[("NO_COLOR", "1")])
+    } else {
+        run_preview_in_terminal(args, &[])
+    }
+}
+
+/// Run a preview with the terminal described only by `envs`
+fn run_preview_in_terminal(args: &[&str], envs: &[(&str, &str)]) -> String {
+    run_preview_with_config(args, envs, "")
+}
+
+/// Like `run_preview_in_terminal`, writing `config` to config.toml first
+fn run_preview_with_config(args: &[&str], envs: &[(&str, &str)], config: &str) -> String {
+    let config_home = make_temp_dir("giallo-kak-preview-config");
+    if !config.is_empty() {
+        let cfg_dir = config_home.join("giallo.kak");
+        fs::create_dir_all(&cfg_dir).expect("failed to create config dir");
+        fs::write(cfg_dir.join("config.toml"), config).expect("failed to write config");
+    }
+
+    let bin = env!("CARGO_BIN_EXE_giallo-kak");
+    let mut cmd = Command::new(bin);
+    cmd.arg("preview")
+        .args(args)
+        .env("XDG_CONFIG_HOME", &config_home)
+        .env_remove("NO_COLOR")
+        .env_remove("COLORTERM")
+        .env_remove("TERM")
+        .env_remove("TERM_PROGRAM")
+        .envs(envs.iter().copied());
+
+    let output = cmd.output().expect("failed to run giallo-kak preview");
+    assert!(output.status.success(), "giallo-kak preview failed");
+
+    let _ = fs::remove_dir_all(&config_home);
+
+    String::from_utf8_lossy(&output.stdout).to_string()
+}
+
+/// Write a theme whose colors have known xterm-256 and ANSI-16 matches,
+/// returning the config line that loads it
+fn write_reference_theme(themes_dir: &Path) -> String {
+    fs::create_dir_all(themes_dir).expect("failed to create themes dir");
+    let contents = r##"{
+  "name": "giallo-reference",
+  "type": "dark",
+  "colors": {
+    "editor.background": "#000000",
+    "editor.foreground": "#ffffff"
+  },
+  "tokenColors": [
+    { "scope": "keyword", "settings": { "foreground": "#ff0000" } },
+    { "scope": "string", "settings": { "foreground": "#5f87af" } },
+    { "scope": "comment", "settings": { "foreground": "#808080" } },
+    { "scope": "entity.name.function", "settings": { "foreground": "#777777" } }
+  ]
+}"##;
+    fs::write(themes_dir.join("giallo-reference.json"), contents).expect("failed to write theme");
+    format!("themes_path = \"{}\"\n", themes_dir.display())
+}
+
+/// Preview a small Rust file with the reference theme
+fn run_reference_preview(colors: &str) -> String {
+    let dir = make_temp_dir("giallo-kak-preview-reference");
+    let config = write_reference_theme(&dir.join("themes"));
+    let source = dir.join("reference.rs");
+    fs::write(&source, "fn main() {\n    let s = \"x\";\n}\n// c\n")
+        .expect("failed to write source");
+
+    let output = run_preview_with_config(
+        &[
+            source.to_str().unwrap(),
+            "--theme",
+            "giallo-reference",
+            "--colors",
+            colors,
+        ],
+        &[],
+        &config,
+    );
+    let _ = fs::remove_dir_all(&dir);
+    output
+}
+
+/// Remove SGR escape sequences, leaving the previewed text
+fn strip_ansi(input: &str) -> String {
+    let mut out = String::with_capacity(input.len());
+    let mut chars = input.chars();
+    while let Some(c) = chars.next() {
+        if c == '\x1b' {
+            for c in chars.by_ref() {
+                if c == 'm' {
+                    break;
+                }
+            }
+        } else {
+            out.push(c);
+        }
+    }
+    out
+}
+
+#[test]
+fn preview_truecolor_keeps_text() {
+    let path = fixture_path("rust_sample.rs");
+    let code = fs::read_to_string(&path).expect("failed to read fixture");
+    let output = run_preview(&[path.to_str().unwrap(), "--colors", "truecolor"], false);
+
+    assert!(
+        output.contains("\x1b[38;2;"),
+        "truecolor preview should use 24-bit foreground sequences"
+    );
+    assert_eq!(
+        strip_ansi(&output),
+        code,
+        "preview should not alter the text"
+    );
+}
+
+#[test]
+fn preview_256_colors() {
+    let path = fixture_path("python_sample.py");
+    let output = run_preview(&[path.to_str().unwrap(), "--colors", "256"], false);
+
+    assert!(
+        output.contains("\x1b[38;5;") || output.contains(";38;5;"),
+        "256-color preview should use indexed foreground sequences"
+    );
+    assert!(
+        !output.contains("38;2;"),
+        "256-color preview should not emit 24-bit sequences"
+    );
+}
+
+#[test]
+fn preview_16_colors() {
+    let path = fixture_path("go_sample.go");
+    let output = run_preview(&[path.to_str().unwrap(), "--colors", "16"], false);
+
+    assert!(
+        output.contains("\x1b["),
+        "16-color preview should be colored"
+    );
+    assert!(
+        !output.contains("38;2;") && !output.contains("38;5;"),
+        "16-color preview should only use basic ANSI colors"
+    );
+}
+
+#[test]
+fn preview_detects_truecolor_from_colorterm() {
+    let path = fixture_path("rust_sample.rs");
+    let output = run_preview_in_terminal(
+        &[path.to_str().unwrap()],
+        &[("COLORTERM", "truecolor"), ("TERM", "xterm-256color")],
+    );
+
+    assert!(
+        output.contains("38;2;"),
+        "COLORTERM=truecolor should select 24-bit colors"
+    );
+}
+
+#[test]
+fn preview_detects_256_colors_from_term() {
+    let path = fixture_path("rust_sample.rs");
+    let output = run_preview_in_terminal(&[path.to_str().unwrap()], &[("TERM", "xterm-256color")]);
+
+    assert!(
+        output.contains("38;5;"),
+        "TERM=xterm-256color should select 256 colors"
+    );
+    assert!(
+        !output.contains("38;2;"),
+        "TERM=xterm-256color should not select 24-bit colors"
+    );
+}
+
+#[test]
+fn preview_detects_16_colors_on_linux_console() {
+    let path = fixture_path("rust_sample.rs");
+    let output = run_preview_in_terminal(&[path.to_str().unwrap()], &[("TERM", "linux")]);
+
+    assert!(output.contains("\x1b["), "preview should be colored");
+    assert!(
+        !output.contains("38;2;") && !output.contains("38;5;"),
+        "TERM=linux should select basic ANSI colors"
+    );
+}
+
+#[test]
+fn preview_defaults_to_256_colors_when_unsure() {
+    let path = fixture_path("rust_sample.rs");
+    let output = run_preview_in_terminal(&[path.to_str().unwrap()], &[]);
+
+    assert!(
+        output.contains("38;5;"),
+        "unknown terminals should get 256 colors"
+    );
+}
+
+#[test]
+fn preview_colors_flag_overrides_detection() {
+    let path = fixture_path("rust_sample.rs");
+    let output = run_preview_in_terminal(
+        &[path.to_str().unwrap(), "--colors", "16"],
+        &[("COLORTERM", "truecolor")],
+    );
+
+    assert!(
+        !output.contains("38;2;") && !output.contains("38;5;"),
+        "--colors should override COLORTERM"
+    );
+}
+
+#[test]
+fn preview_respects_no_color() {
+    let path = fixture_path("javascript_sample.js");
+    let code = fs::read_to_string(&path).expect("failed to read fixture");
+    let output = run_preview(&[path.to_str().unwrap()], true);
+
+    assert_eq!(output, code, "NO_COLOR preview should print plain text");
+}
+
+#[test]
+fn preview_options_before_file() {
+    let path = fixture_path("go_sample.go");
+    let output = run_preview(&["--colors", "16", path.to_str().unwrap()], false);
+
+    assert!(output.contains("\x1b["), "preview should be colored");
+    assert!(
+        !output.contains("38;2;") && !output.contains("38;5;"),
+        "--colors before the file should still apply"
+    );
+}
+
+#[test]
+fn preview_explicit_lang_and_theme() {
+    let path = fixture_path("rust_sample.rs");
+    let output = run_preview(
+        &[
+            path.to_str().unwrap(),
+            "--lang",
+            "rust",
+            "--theme",
+            "dracula",
+        ],
+        false,
+    );
+
+    assert!(output.contains("\x1b["), "preview should be colored");
+}
+
+#[test]
+fn preview_truecolor_reference_colors() {
+    let output = run_reference_preview("truecolor");
+
+    assert!(
+        output.contains("\x1b[38;2;255;0;0mfn"),
+        "#ff0000 should be emitted as 255;0;0"
+    );
+    assert!(
+        output.contains("38;2;95;135;175m"),
+        "#5f87af should be emitted as 95;135;175"
+    );
+}
+
+#[test]
+fn preview_256_reference_indices() {
+    let output = run_reference_preview("256");
+
+    // Exact cube and grayscale entries map to their own index
+    assert!(
+        output.contains("\x1b[38;5;196mfn"),
+        "#ff0000 should map to cube index 196"
+    );
+    assert!(
+        output.contains("38;5;67m"),
+        "#5f87af should map to cube index 67"
+    );
+    assert!(
+        output.contains("38;5;244m"),
+        "#808080 should map to grayscale index 244"
+    );
+    // #777777 sits between grayscale 243 (#767676) and 244 (#808080)
+    assert!(
+        output.contains("\x1b[38;5;243mmain"),
+        "#777777 should map to the nearest grayscale index 243"
+    );
+    assert!(
+        !output.contains("38;5;9m") && !output.contains("38;5;8m"),
+        "the remappable base slots should never be used"
+    );
+}
+
+#[test]
+fn preview_16_reference_slots() {
+    let output = run_reference_preview("16");
+
+    assert!(
+        output.contains("\x1b[91mfn"),
+        "#ff0000 should map to bright red (91)"
+    );
+    assert!(
+        output.contains("\x1b[90m"),
+        "#808080 should map to bright black (90)"
+    );
+}
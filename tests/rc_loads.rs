@@ -0,0 +1,95 @@
+//! Checks that the Kakoune integration script loads in a headless Kakoune
+//!
+//! Catches syntax errors in `rc/giallo.kak` (bad option types, unbalanced
+//! braces, unknown commands) without needing a full E2E session. Skipped when
+//! Kakoune is not installed.
+
+use std::fs;
+use std::path::{Path, PathBuf};
+use std::process::{Command, Stdio};
+use std::thread;
+use std::time::{Duration, Instant};
+use tempfile::TempDir;
+
+fn kakoune_available() -> bool {
+    Command::new("kak").arg("-version").output().is_ok()
+}
+
+/// Source `script` in a headless Kakoune and return any error it raised,
+/// together with whatever Kakoune printed on stderr
+fn source_in_kak(script: &Path) -> (String, String) {
+    let temp_dir = TempDir::new().expect("failed to create temp dir");
+    let error_path = temp_dir.path().join("error.txt");
+
+    let commands = format!(
+        "try %{{ source '{}' }} catch %{{ echo -to-file '{}' -- %val{{error}} }}; quit!",
+        script.display(),
+        error_path.display()
+    );
+
+    let mut child = Command::new("kak")
+        .args(["-n", "-ui", "dummy", "-e", &commands])
+        .env("KAKOUNE_CONFIG_DIR", temp_dir.path())
+        .stdin(Stdio::null())
+        .stdout(Stdio::null())
+        .stderr(Stdio::piped())
+        .spawn()
+        .expect("failed to spawn kak");
+
+    let start = Instant::now();
+    while child.try_wait().expect("failed to poll kak").is_none() {
+        if start.elapsed() > Duration::from_secs(10) {
+            let _ = child.kill();
+            panic!("kak did not exit after sourcing {}", script.display());
+        }
+        thread::sleep(Duration::from_millis(50));
+    }
+
+    let output = child.wait_with_output().expect("failed to read kak output");
+    let error = fs::read_to_string(&error_path).unwrap_or_default();
+    (error, String::from_utf8_lossy(&output.stderr).to_string())
+}
+
+#[test]
+fn rc_sources_cleanly() {
+    if !kakoune_available() {
+        println!("Skipping rc load test: Kakoune not installed");
+        return;
+    }
+
+    let rc = PathBuf::from(env!("CARGO_MANIFEST_DIR"))
+        .join("rc")
+        .join("giallo.kak");
+
+    let (error, stderr) = source_in_kak(&rc);
+    assert!(
+        error.trim().is_empty(),
+        "sourcing giallo.kak failed: {error}"
+    );
+    assert!(stderr.trim().is_empty(), "kak wrote to stderr: {stderr}");
+}
+
+#[test]
+fn init_output_sources_cleanly() {
+    if !kakoune_available() {
+        println!("Skipping rc load test: Kakoune not installed");
+        return;
+    }
+
+    let output = Command::new(env!("CARGO_BIN_EXE_giallo-kak"))
+        .arg("init")
+        .output()
+        .expect("failed to run giallo-kak init");
+    assert!(output.status.success(), "giallo-kak init failed");
+
+    let temp_dir = TempDir::new().expect("failed to create temp dir");
+    let script = temp_dir.path().join("init.kak");
+    fs::write(&script, &output.stdout).expect("failed to write init script");
+
+    let (error, stderr) = source_in_kak(&script);
+    assert!(
+        error.trim().is_empty(),
+        "sourcing giallo-kak init output failed: {error}"
+    );
+    assert!(stderr.trim().is_empty(), "kak wrote to stderr: {stderr}");
+}
@@ -0,0 +1,470 @@
+//! Golden snapshot tests for fixture highlighting
+//!
+//! Every `*_sample.*` file in `tests/fixtures/` is highlighted in oneshot mode
+//! and the resulting face spans are compared against a committed snapshot in
+//! `tests/snapshots/<fixture>.golden`. A missing snapshot is a failure; set
+//! `GIALLO_UPDATE_SNAPSHOTS=1` to write snapshots for new fixtures or rewrite
+//! them after an intended change, then commit the `.golden` files.
+//! Fixtures whose grammar the registry doesn't have are skipped and listed on
+//! stderr, while a fixture that highlights to nothing but the default face
+//! fails.
+//!
+//! `fixture_coverage` cross-checks the fixtures against `list-grammars`, so
+//! stray fixture files and grammars without a sample are reported.
+
+use std::collections::HashMap;
+use std::fs;
+use std::io::Write;
+use std::path::{Path, PathBuf};
+use std::process::{Command, Stdio};
+
+const SNAPSHOT_THEME: &str = "catppuccin-frappe";
+
+/// Files in `tests/fixtures/` that are not highlighting samples
+const HELPER_FILES: &[&str] = &["generate_perf_fixtures.rs"];
+
+/// Grammars reported by `list-grammars` that have no sample fixture yet.
+/// Adding a fixture for one of these requires removing it from this list.
+const UNTESTED_GRAMMARS: &[&str] = &[
+    // Alias of shellscript
+    "bash",
+    "json",
+    "cpp",
+    "java",
+    "ruby",
+    "php",
+    "scss",
+    "xml",
+    // Covered by terraform_oneshot.rs with a custom grammar
+    "terraform",
+    "hcl",
+    "vim",
+    "regex",
+    "make",
+    "cmake",
+    "git-rebase",
+    "graphql",
+    "swift",
+    "kotlin",
+    "scala",
+    "clojure",
+    "erlang",
+    "elixir",
+    "ocaml",
+    "fsharp",
+    "r",
+    "matlab",
+    "julia",
+    "perl",
+];
+
+fn make_temp_dir(prefix: &str) -> PathBuf {
+    let mut dir = std::env::temp_dir();
+    let unique = format!(
+        "{}-{}-{}",
+        prefix,
+        std::process::id(),
+        std::time::SystemTime::now()
+            .duration_since(std::time::UNIX_EPOCH)
+            .unwrap_or_default()
+            .as_nanos()
+    );
+    dir.push(unique);
+    fs::create_dir_all(&dir).expect("failed to create temp dir");
+    dir
+}
+
+fn write_config(config_dir: &Path, theme: &str) {
+    let cfg_dir = config_dir.join("giallo.kak");
+    fs::create_dir_all(&cfg_dir).expect("failed to create config dir");
+    let config_path = cfg_dir.join("config.toml");
+    let contents = format!("theme = \"{}\"\n", theme);
+    fs::write(&config_path, contents).expect("failed to write config");
+}
+
+fn run_oneshot_highlight(lang: &str, theme: &str, code: &str) -> String {
+    let config_home = make_temp_dir("giallo-kak-snapshot-config");
+    write_config(&config_home, theme);
+
+    let payload = code.as_bytes();
+    let header = format!("H {} {} {}\n", lang, theme, payload.len());
+
+    let bin = env!("CARGO_BIN_EXE_giallo-kak");
+    let mut child = Command::new(bin)
+        .arg("--oneshot")
+        .env("XDG_CONFIG_HOME", &config_home)
+        .stdin(Stdio::piped())
+        .stdout(Stdio::piped())
+        .spawn()
+        .expect("failed to spawn giallo-kak");
+
+    {
+        let stdin = child.stdin.as_mut().expect("failed to open stdin");
+        stdin
+            .write_all(header.as_bytes())
+            .expect("failed to write header");
+        stdin.write_all(payload).expect("failed to write payload");
+    }
+
+    let output = child
+        .wait_with_output()
+        .expect("failed to read giallo-kak output");
+
+    assert!(output.status.success(), "giallo-kak failed");
+
+    let _ = fs::remove_dir_all(&config_home);
+
+    String::from_utf8_lossy(&output.stdout).to_string()
+}
+
+/// Whether the registry has `lang`, asked with the hidden `has-grammar`
+/// command. `list-grammars` only prints a curated subset, so it can't tell
+/// a missing grammar from one that is just not listed.
+fn has_grammar(lang: &str) -> bool {
+    let config_home = make_temp_dir("giallo-kak-snapshot-config");
+
+    let bin = env!("CARGO_BIN_EXE_giallo-kak");
+    let status = Command::new(bin)
+        .args(["has-grammar", lang])
+        .env("XDG_CONFIG_HOME", &config_home)
+        .status()
+        .expect("failed to run giallo-kak has-grammar");
+
+    let _ = fs::remove_dir_all(&config_home);
+
+    match status.code() {
+        Some(0) => true,
+        Some(1) => false,
+        _ => panic!("giallo-kak has-grammar {lang} failed: {status}"),
+    }
+}
+
+/// Builtin grammars as reported by `giallo-kak list-grammars --plain`
+fn list_grammars() -> Vec<String> {
+    let config_home = make_temp_dir("giallo-kak-snapshot-config");
+
+    let bin = env!("CARGO_BIN_EXE_giallo-kak");
+    let output = Command::new(bin)
+        .args(["list-grammars", "--plain"])
+        .env("XDG_CONFIG_HOME", &config_home)
+        .output()
+        .expect("failed to run giallo-kak list-grammars");
+
+    assert!(output.status.success(), "giallo-kak list-grammars failed");
+
+    let _ = fs::remove_dir_all(&config_home);
+
+    String::from_utf8_lossy(&output.stdout)
+        .lines()
+        .map(|line| line.trim().to_string())
+        .filter(|line| !line.is_empty())
+        .collect()
+}
+
+/// Map a fixture file name to the giallo language used to highlight it
+fn fixture_lang(file_name: &str) -> Option<&'static str> {
+    // Fixtures without a telling extension are matched by name
+    match file_name {
+        "dockerfile_sample" => return Some("docker"),
+        "gitconfig_sample" => return Some("ini"),
+        "commit_sample.txt" => return Some("git-commit"),
+        _ => {}
+    }
+
+    let (_, ext) = file_name.rsplit_once('.')?;
+    match ext {
+        "rs" => Some("rust"),
+        "js" => Some("javascript"),
+        "ts" => Some("typescript"),
+        "tsx" => Some("tsx"),
+        "py" => Some("python"),
+        "go" => Some("go"),
+        "c" => Some("c"),
+        "diff" => Some("diff"),
+        "md" => Some("markdown"),
+        "yaml" | "yml" => Some("yaml"),
+        "sql" => Some("sql"),
+        "toml" => Some("toml"),
+        "sh" => Some("shellscript"),
+        "html" => Some("html"),
+        "css" => Some("css"),
+        "lua" => Some("lua"),
+        "nix" => Some("nix"),
+        "jsonc" => Some("jsonc"),
+        "json5" => Some("json5"),
+        "proto" => Some("proto"),
+        "csv" => Some("csv"),
+        "tsv" => Some("tsv"),
+        "hs" => Some("haskell"),
+        "log" => Some("log"),
+        _ => None,
+    }
+}
+
+fn fixtures_dir() -> PathBuf {
+    Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("fixtures")
+}
+
+fn snapshots_dir() -> PathBuf {
+    Path::new(env!("CARGO_MANIFEST_DIR"))
+        .join("tests")
+        .join("snapshots")
+}
+
+/// Sample fixtures, sorted by file name. Helper sources such as the perf
+/// fixture generator live next to them and are not snapshotted.
+fn discover_fixtures() -> Vec<PathBuf> {
+    let entries = fs::read_dir(fixtures_dir()).expect("failed to read fixtures dir");
+    let mut fixtures: Vec<PathBuf> = entries
+        .filter_map(|e| e.ok())
+        .map(|e| e.path())
+        .filter(|path| path.is_file())
+        .filter(|path| {
+            path.file_name()
+                .and_then(|n| n.to_str())
+                .is_some_and(|n| n.contains("_sample"))
+        })
+        .collect();
+    fixtures.sort();
+    fixtures
+}
+
+/// Render oneshot output as one `range face-spec` line per highlighted span,
+/// resolving generated face names so snapshots don't depend on face numbering.
+fn render_snapshot(output: &str) -> String {
+    let mut specs: HashMap<&str, &str> = HashMap::new();
+    for line in output.lines() {
+        let Some(rest) = line.strip_prefix("set-face global ") else {
+            continue;
+        };
+        let Some((name, spec)) = rest.split_once(' ') else {
+            continue;
+        };
+        let spec = spec.trim_start_matches("%{").trim_end_matches('}');
+        specs.insert(name, spec);
+    }
+
+    let ranges_line = output
+        .lines()
+        .find(|line| line.starts_with("set-option buffer giallo_hl_ranges"))
+        .expect("should have ranges line");
+
+    let mut snapshot = String::new();
+    // Skip "set-option", "buffer", "giallo_hl_ranges", and timestamp
+    for range in ranges_line.split_whitespace().skip(4) {
+        let Some((span, face)) = range.split_once('|') else {
+            continue;
+        };
+        let spec = specs.get(face).copied().unwrap_or(face);
+        snapshot.push_str(&format!("{span} {spec}\n"));
+    }
+    snapshot
+}
+
+/// Describe the first line where two snapshots disagree
+fn first_difference(expected: &str, actual: &str) -> String {
+    let mut expected_lines = expected.lines();
+    let mut actual_lines = actual.lines();
+    let mut line_no = 1;
+    loop {
+        match (expected_lines.next(), actual_lines.next()) {
+            (Some(e), Some(a)) if e == a => line_no += 1,
+            (None, None) => return String::from("snapshots are identical"),
+            (e, a) => {
+                return format!(
+                    "line {line_no}:\n  expected: {}\n  actual:   {}",
+                    e.unwrap_or("<end of snapshot>"),
+                    a.unwrap_or("<end of output>")
+                );
+            }
+        }
+    }
+}
+
+/// Result of checking one fixture against its golden snapshot
+#[derive(Debug, PartialEq)]
+enum FixtureOutcome {
+    /// Highlighting matches the golden snapshot
+    Matched,
+    /// The snapshot was written because `GIALLO_UPDATE_SNAPSHOTS=1` is set
+    Written,
+    /// No snapshot is committed for the fixture
+    Missing,
+    /// No grammar is available for the fixture's language
+    Skipped(String),
+    /// The grammar ran but every span uses the default face
+    Empty,
+    /// Highlighting differs from the golden snapshot
+    Mismatch(String),
+}
+
+fn check_fixture(file_name: &str, lang: &str, code: &str, update: bool) -> FixtureOutcome {
+    // giallo-kak falls back to plain text for unknown grammars, so check up
+    // front rather than snapshotting an unhighlighted file
+    if !has_grammar(lang) {
+        return FixtureOutcome::Skipped(format!("grammar `{lang}` is not available"));
+    }
+
+    let output = run_oneshot_highlight(lang, SNAPSHOT_THEME, code);
+    let actual = render_snapshot(&output);
+    if actual.lines().all(|line| line.ends_with(" default")) {
+        return FixtureOutcome::Empty;
+    }
+
+    let golden = snapshots_dir().join(format!("{file_name}.golden"));
+    if update {
+        fs::create_dir_all(snapshots_dir()).expect("failed to create snapshots dir");
+        fs::write(&golden, &actual).expect("failed to write snapshot");
+        return FixtureOutcome::Written;
+    }
+    if !golden.exists() {
+        return FixtureOutcome::Missing;
+    }
+
+    let expected = fs::read_to_string(&golden).expect("failed to read snapshot");
+    if expected == actual {
+        FixtureOutcome::Matched
+    } else {
+        FixtureOutcome::Mismatch(format!(
+            "highlighting differs from {}\n{}",
+            golden.display(),
+            first_difference(&expected, &actual)
+        ))
+    }
+}
+
+#[test]
+fn fixture_snapshots() {
+    let update = std::env::var("GIALLO_UPDATE_SNAPSHOTS").is_ok_and(|v| v == "1");
+    let fixtures = discover_fixtures();
+    assert!(!fixtures.is_empty(), "no fixtures found");
+    // One actionable failure instead of a missing snapshot per fixture
+    assert!(
+        update || snapshots_dir().is_dir(),
+        "tests/snapshots/ does not exist; generate it with `GIALLO_UPDATE_SNAPSHOTS=1 cargo test --test snapshot_tests`, review the .golden files and commit them"
+    );
+
+    let mut failures: Vec<String> = Vec::new();
+    let mut skipped: Vec<String> = Vec::new();
+
+    for fixture in &fixtures {
+        let file_name = fixture
+            .file_name()
+            .and_then(|n| n.to_str())
+            .expect("fixture name should be valid utf-8");
+        let Some(lang) = fixture_lang(file_name) else {
+            failures.push(format!("{file_name}: no language mapping for fixture"));
+            continue;
+        };
+
+        let code = fs::read_to_string(fixture).expect("failed to read fixture");
+        match check_fixture(file_name, lang, &code, update) {
+            FixtureOutcome::Matched => {}
+            FixtureOutcome::Written => println!("wrote snapshot for {file_name}"),
+            FixtureOutcome::Missing => failures.push(format!(
+                "{file_name}: no snapshot at tests/snapshots/{file_name}.golden"
+            )),
+            FixtureOutcome::Skipped(reason) => skipped.push(format!("{file_name}: {reason}")),
+            FixtureOutcome::Empty => {
+                failures.push(format!("{file_name}: `{lang}` produced no highlighting"))
+            }
+            FixtureOutcome::Mismatch(diff) => failures.push(format!("{file_name}: {diff}")),
+        }
+    }
+
+    // Written straight to the stderr handle, which libtest does not capture,
+    // so skips show up without --nocapture
+    if !skipped.is_empty() {
+        let mut stderr = std::io::stderr().lock();
+        let _ = writeln!(stderr, "skipped {} fixture(s):", skipped.len());
+        for line in &skipped {
+            let _ = writeln!(stderr, "  {line}");
+        }
+    }
+
+    assert!(
+        failures.is_empty(),
+        "snapshot mismatches (rerun with GIALLO_UPDATE_SNAPSHOTS=1 if intended):\n{}",
+        failures.join("\n")
+    );
+}
+
+#[test]
+fn fixture_with_unavailable_grammar_is_skipped() {
+    let outcome = check_fixture(
+        "unknown_sample.nosuchlang",
+        "nosuchlang",
+        "some text\n",
+        false,
+    );
+
+    assert_eq!(
+        outcome,
+        FixtureOutcome::Skipped(String::from("grammar `nosuchlang` is not available"))
+    );
+}
+
+#[test]
+fn fixture_without_highlighting_is_empty() {
+    assert!(has_grammar("rust"), "rust grammar should be available");
+
+    // The grammar exists, but there is nothing to highlight
+    let outcome = check_fixture("blank_sample.rs", "rust", "\n\n", false);
+
+    assert_eq!(outcome, FixtureOutcome::Empty);
+}
+
+#[test]
+fn fixture_coverage() {
+    let mut orphans: Vec<String> = Vec::new();
+    let mut covered: Vec<&str> = Vec::new();
+
+    let entries = fs::read_dir(fixtures_dir()).expect("failed to read fixtures dir");
+    let mut names: Vec<String> = entries
+        .filter_map(|e| e.ok())
+        .filter(|e| e.path().is_file())
+        .map(|e| e.file_name().to_string_lossy().to_string())
+        .collect();
+    names.sort();
+
+    for name in &names {
+        if HELPER_FILES.contains(&name.as_str()) {
+            continue;
+        }
+        match fixture_lang(name) {
+            Some(lang) if name.contains("_sample") => covered.push(lang),
+            _ => orphans.push(name.clone()),
+        }
+    }
+
+    let grammars = list_grammars();
+    let untested: Vec<&String> = grammars
+        .iter()
+        .filter(|g| !covered.contains(&g.as_str()) && !UNTESTED_GRAMMARS.contains(&g.as_str()))
+        .collect();
+    let stale: Vec<&&str> = UNTESTED_GRAMMARS
+        .iter()
+        .filter(|g| covered.contains(*g))
+        .collect();
+
+    let mut problems: Vec<String> = Vec::new();
+    if !orphans.is_empty() {
+        problems.push(format!(
+            "fixtures not picked up by the snapshot harness (name them *_sample.<ext> and map them in fixture_lang()): {orphans:?}"
+        ));
+    }
+    if !untested.is_empty() {
+        problems.push(format!(
+            "grammars without a sample fixture (add one or list them in UNTESTED_GRAMMARS): {untested:?}"
+        ));
+    }
+    if !stale.is_empty() {
+        problems.push(format!(
+            "grammars in UNTESTED_GRAMMARS that now have a fixture: {stale:?}"
+        ));
+    }
+
+    assert!(problems.is_empty(), "{}", problems.join("\n"));
+}
@@ -88,6 +88,35 @@ fn assert_has_ranges(output: &str) {
     );
 }
 
+/// Face assigned to the byte at 1-based `line`/`col`, if a range covers it
+fn face_at(output: &str, line: usize, col: usize) -> Option<String> {
+    let ranges_line = output
+        .lines()
+        .find(|line| line.starts_with("set-option buffer giallo_hl_ranges"))
+        .expect("should have ranges line");
+
+    // Skip "set-option", "buffer", "giallo_hl_ranges", and timestamp
+    for range in ranges_line.split_whitespace().skip(4) {
+        let Some((span, face)) = range.split_once('|') else {
+            continue;
+        };
+        let Some((start, end)) = span.split_once(',') else {
+            continue;
+        };
+        let parse = |pos: &str| -> Option<(usize, usize)> {
+            let (l, c) = pos.split_once('.')?;
+            Some((l.parse().ok()?, c.parse().ok()?))
+        };
+        let (Some((start_line, start_col)), Some((_, end_col))) = (parse(start), parse(end)) else {
+            continue;
+        };
+        if start_line == line && start_col <= col && col <= end_col {
+            return Some(face.to_string());
+        }
+    }
+    None
+}
+
 #[test]
 fn rust_string_highlighting() {
     let code = r#"fn main() {
@@ -118,6 +147,61 @@ fn rust_keyword_highlighting() {
     assert_has_ranges(&output);
 }
 
+#[test]
+fn rust_lifetime_vs_char_highlighting() {
+    let code = r#"struct Wrapper<'a> {
+    name: &'a str,
+}
+fn main() {
+    let letter = 'a';
+    let after = "plain";
+}"#;
+
+    let output = run_oneshot_highlight("rust", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let lifetime = face_at(&output, 2, 13).expect("lifetime should be highlighted");
+    let char_literal = face_at(&output, 5, 19).expect("char literal should be highlighted");
+    assert_ne!(
+        lifetime, char_literal,
+        "lifetime should not be highlighted as a char literal"
+    );
+}
+
+#[test]
+fn rust_macro_and_raw_string_highlighting() {
+    let code = r##"macro_rules! square {
+    ($x:expr) => {
+        $x * $x
+    };
+}
+fn parse(input: &str) -> Result<u32, std::num::ParseIntError> {
+    let template = r#"{"key": "value"}"#;
+    let value = input.parse::<u32>()?;
+    Ok(value)
+}"##;
+
+    let output = run_oneshot_highlight("rust", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // The raw string's inner quotes must not end it early. If they did, `{`
+    // would stay string content while `key` and `:` became bare tokens, so
+    // compare `key` against the characters on either side of it.
+    let brace = face_at(&output, 7, 23).expect("raw string content should be highlighted");
+    let key = face_at(&output, 7, 25).expect("raw string content should be highlighted");
+    let colon = face_at(&output, 7, 29).expect("raw string content should be highlighted");
+    assert_eq!(
+        key, brace,
+        "raw string should not terminate at the quote before `key`"
+    );
+    assert_eq!(
+        key, colon,
+        "`:` inside the raw string should stay string content"
+    );
+}
+
 #[test]
 fn javascript_string_highlighting() {
     let code = r#"const greeting = "Hello, world!";
@@ -190,6 +274,55 @@ class MyClass:
     assert_has_ranges(&output);
 }
 
+#[test]
+fn python_fstring_interpolation_highlighting() {
+    let code = r#"width = 10
+label = "plain"
+padded = f"{label!r:>{width}}"
+after = "plain"
+"#;
+
+    let output = run_oneshot_highlight("python", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Nested format specs must not leave the string open: the literal on the
+    // following line gets the same face as the one before the f-string.
+    let before = face_at(&output, 2, 10).expect("string before f-string should be highlighted");
+    let after = face_at(&output, 4, 10).expect("string after f-string should be highlighted");
+    assert_eq!(
+        before, after,
+        "f-string with nested spec leaked into following line"
+    );
+
+    // The interpolated expression is highlighted separately from the string body
+    let quote = face_at(&output, 3, 11).expect("f-string quote should be highlighted");
+    let expr = face_at(&output, 3, 13).expect("f-string expression should be highlighted");
+    assert_ne!(
+        quote, expr,
+        "f-string interpolation should not use the string face"
+    );
+}
+
+#[test]
+fn python_decorator_highlighting() {
+    let code = r#"class Account:
+    @property
+    def balance(self):
+        return self._balance
+"#;
+
+    let output = run_oneshot_highlight("python", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let decorator = face_at(&output, 2, 6).expect("decorator should be highlighted");
+    assert_ne!(
+        decorator, "default",
+        "decorator should not use the default face"
+    );
+}
+
 #[test]
 fn go_string_highlighting() {
     let code = r#"package main
@@ -234,6 +367,62 @@ func test() {
     assert_has_ranges(&output);
 }
 
+#[test]
+fn go_struct_tag_highlighting() {
+    let code = r#"type User struct {
+    Name string `json:"name,omitempty"`
+    Age  int
+}
+"#;
+
+    let output = run_oneshot_highlight("go", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let field_type = face_at(&output, 2, 10).expect("field type should be highlighted");
+    let tag = face_at(&output, 2, 18).expect("struct tag should be highlighted");
+    assert_ne!(field_type, tag, "struct tag should not use the type face");
+
+    // The closing backtick ends the tag, the next field is highlighted normally
+    let next_type = face_at(&output, 3, 10).expect("next field type should be highlighted");
+    assert_ne!(
+        next_type, tag,
+        "struct tag should not swallow the following field"
+    );
+}
+
+#[test]
+fn go_constraint_operator_highlighting() {
+    // The constraint line from go_generics_sample.go, indented with a tab
+    let code = "type Key interface {\n\t~int | ~string\n}\n\nvar mask = 1 | 2\n";
+
+    let output = run_oneshot_highlight("go", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let tilde = face_at(&output, 2, 2).expect("`~` should be highlighted");
+    let int_type = face_at(&output, 2, 3).expect("`int` should be highlighted");
+    let union = face_at(&output, 2, 7).expect("`|` should be highlighted");
+    let string_type = face_at(&output, 2, 10).expect("`string` should be highlighted");
+    let bitwise_or = face_at(&output, 5, 14).expect("`|` in an expression should be highlighted");
+
+    assert_ne!(tilde, "default", "`~` should use the operator face");
+    assert_ne!(tilde, int_type, "`~` should not be swallowed into the type");
+    assert_eq!(
+        union, bitwise_or,
+        "`|` in a constraint should use the operator face"
+    );
+    assert_eq!(
+        face_at(&output, 2, 9),
+        Some(tilde),
+        "every `~` element should be highlighted the same"
+    );
+    assert_eq!(
+        string_type, int_type,
+        "the type after `| ~` should keep the type face"
+    );
+}
+
 #[test]
 fn typescript_string_highlighting() {
     let code = r#"const greeting: string = "Hello, world!";
@@ -275,6 +464,529 @@ const value: ID = 42;"#;
     assert_has_ranges(&output);
 }
 
+#[test]
+fn typescript_nested_template_literal_highlighting() {
+    let code = r#"const first = "plain";
+const nested = `outer ${`inner ${first.length}`} and ${first === "x" ? `y` : "z"}`;
+const markup = "<div>not a tag</div>";
+const after = "plain";"#;
+
+    let output = run_oneshot_highlight("typescript", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Nested backticks and `</div>` inside a string must not leave a string
+    // region open past their own line.
+    let first = face_at(&output, 1, 16).expect("string before template should be highlighted");
+    let after = face_at(&output, 4, 16).expect("string after template should be highlighted");
+    assert_eq!(
+        first, after,
+        "nested template literal leaked into following lines"
+    );
+
+    // Interpolated expressions are highlighted as code, not as template text
+    let text = face_at(&output, 2, 17).expect("template text should be highlighted");
+    let expr = face_at(&output, 2, 56).expect("interpolated expression should be highlighted");
+    assert_ne!(
+        text, expr,
+        "template interpolation should not use the string face"
+    );
+}
+
+#[test]
+fn markdown_fenced_code_uses_language_grammar() {
+    let code = r#"Some text.
+
+```rust
+fn main() { println!("hi"); }
+```
+"#;
+
+    let output = run_oneshot_highlight("markdown", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // A plain gray block would give `fn` and the string literal the same face
+    let keyword = face_at(&output, 4, 1).expect("fenced keyword should be highlighted");
+    let string = face_at(&output, 4, 23).expect("fenced string should be highlighted");
+    assert_ne!(
+        keyword, string,
+        "fenced rust code should be highlighted with the rust grammar"
+    );
+}
+
+#[test]
+fn c_preprocessor_continuation_highlighting() {
+    let code = r#"#define FOO 1
+#if defined(FOO) && \
+    defined(BAR)
+int main(void) {
+    if (FOO) {
+        return 0;
+    }
+    return 1;
+}"#;
+
+    let output = run_oneshot_highlight("c", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // The macro name is not a keyword
+    let name = face_at(&output, 1, 9).expect("macro name should be highlighted");
+    let keyword = face_at(&output, 6, 9).expect("`return` should be highlighted");
+    assert_ne!(name, keyword, "macro name should not use the keyword face");
+
+    // After a trailing `\` the directive goes on, so `defined` on the next
+    // line is still the directive operator rather than a function call
+    let first = face_at(&output, 2, 5).expect("`defined` should be highlighted");
+    let continued = face_at(&output, 3, 5).expect("continued `defined` should be highlighted");
+    assert_eq!(
+        first, continued,
+        "continued directive line should keep the directive face"
+    );
+}
+
+#[test]
+fn yaml_block_scalar_highlighting() {
+    let code = r#"script: |
+  echo start
+  not_a_key: true
+anchor: &base
+  value: 1
+alias: *base
+"#;
+
+    let output = run_oneshot_highlight("yaml", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Lines inside a block scalar are string content, even when they look like keys
+    let body = face_at(&output, 2, 3).expect("block scalar body should be highlighted");
+    let key_like = face_at(&output, 3, 3).expect("block scalar body should be highlighted");
+    assert_eq!(
+        body, key_like,
+        "block scalar body should not be parsed as keys"
+    );
+
+    let key = face_at(&output, 4, 1).expect("mapping key should be highlighted");
+    assert_ne!(
+        key, key_like,
+        "real keys should differ from block scalar content"
+    );
+}
+
+#[test]
+fn sql_mixed_case_keyword_highlighting() {
+    let code = r#"SELECT id
+from users
+Where name = 'O''Brien';
+"#;
+
+    let output = run_oneshot_highlight("sql", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let upper = face_at(&output, 1, 1).expect("SELECT should be highlighted");
+    let lower = face_at(&output, 2, 1).expect("from should be highlighted");
+    let mixed = face_at(&output, 3, 1).expect("Where should be highlighted");
+    assert_ne!(upper, "default", "keywords should not use the default face");
+    assert_eq!(upper, lower, "keywords should match case-insensitively");
+    assert_eq!(upper, mixed, "keywords should match case-insensitively");
+}
+
+#[test]
+fn toml_keys_and_strings_highlighting() {
+    let code = r#"url = "https://example.com/#not-a-comment"
+site.owner.name = "Yukai"
+[servers.alpha]
+"#;
+
+    let output = run_oneshot_highlight("toml", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // `#` inside a string does not start a comment
+    let string = face_at(&output, 1, 8).expect("string should be highlighted");
+    let hash = face_at(&output, 1, 28).expect("string should be highlighted");
+    assert_eq!(string, hash, "# inside a string should stay a string");
+
+    // Every segment of a dotted key is colored as a key
+    let first = face_at(&output, 2, 1).expect("dotted key should be highlighted");
+    let last = face_at(&output, 2, 12).expect("dotted key should be highlighted");
+    assert_eq!(
+        first, last,
+        "the whole dotted key path should use the key face"
+    );
+    assert_ne!(first, string, "keys should differ from string values");
+}
+
+#[test]
+fn shell_quoting_expansion_highlighting() {
+    let code = r#"greeting="Hello, $name"
+literal='Hello, $name'
+length=${#name} # real comment
+"#;
+
+    let output = run_oneshot_highlight("shellscript", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Expansions are highlighted inside double quotes
+    let double = face_at(&output, 1, 11).expect("double-quoted string should be highlighted");
+    let expanded = face_at(&output, 1, 19).expect("expansion should be highlighted");
+    assert_ne!(
+        double, expanded,
+        "$var inside double quotes should be highlighted"
+    );
+
+    // ...but stay literal inside single quotes
+    let single = face_at(&output, 2, 10).expect("single-quoted string should be highlighted");
+    let literal = face_at(&output, 2, 18).expect("single-quoted string should be highlighted");
+    assert_eq!(
+        single, literal,
+        "$var inside single quotes should stay literal"
+    );
+
+    // `#` in ${#var} is not a comment, the one after the command is
+    let length = face_at(&output, 3, 10).expect("length expansion should be highlighted");
+    let comment = face_at(&output, 3, 17).expect("comment should be highlighted");
+    assert_ne!(
+        length, comment,
+        "# inside ${{#var}} should not start a comment"
+    );
+}
+
+#[test]
+fn html_attribute_value_with_angle_bracket() {
+    let code = r#"<a title="a > b" href="/x">link</a>
+"#;
+
+    let output = run_oneshot_highlight("html", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // `>` inside the quoted value doesn't close the tag, so `href` is still
+    // highlighted as an attribute name
+    let title = face_at(&output, 1, 4).expect("attribute name should be highlighted");
+    let href = face_at(&output, 1, 18).expect("attribute after value should be highlighted");
+    assert_eq!(
+        title, href,
+        "> in an attribute value should not close the tag"
+    );
+}
+
+#[test]
+fn html_embedded_style_and_script() {
+    let code = r#"<style>p { color: red; }</style>
+<script>const x = "y";</script>
+<p>color const</p>
+"#;
+
+    let output = run_oneshot_highlight("html", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // The same words are highlighted inside <style>/<script> but are plain
+    // text in the body
+    let property = face_at(&output, 1, 12).expect("CSS property should be highlighted");
+    let keyword = face_at(&output, 2, 9).expect("JS keyword should be highlighted");
+    assert_ne!(
+        Some(property),
+        face_at(&output, 3, 4),
+        "<style> content should be highlighted as CSS"
+    );
+    assert_ne!(
+        Some(keyword),
+        face_at(&output, 3, 10),
+        "<script> content should be highlighted as JavaScript"
+    );
+}
+
+#[test]
+fn lua_long_bracket_level_matching() {
+    let code = r#"local s = [==[a ]] b]==]
+local t = 1
+--[==[ x ]] y ]==]
+local u = 2
+"#;
+
+    let output = run_oneshot_highlight("lua", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // `]]` doesn't close a level-2 long bracket string...
+    let string = face_at(&output, 1, 15).expect("long string should be highlighted");
+    let still_string = face_at(&output, 1, 20).expect("long string should be highlighted");
+    assert_eq!(string, still_string, "]] should not close a [==[ string");
+    let keyword = face_at(&output, 2, 1).expect("keyword should be highlighted");
+    assert_ne!(string, keyword, "]==] should close the string");
+
+    // ...or a level-2 long bracket comment
+    let comment = face_at(&output, 3, 8).expect("long comment should be highlighted");
+    let still_comment = face_at(&output, 3, 13).expect("long comment should be highlighted");
+    assert_eq!(
+        comment, still_comment,
+        "]] should not close a --[==[ comment"
+    );
+    let keyword = face_at(&output, 4, 1).expect("keyword should be highlighted");
+    assert_ne!(comment, keyword, "]==] should close the comment");
+}
+
+#[test]
+fn gitconfig_ini_highlighting() {
+    let code = "# comment\n[user]\n\tname = Giallo Tester\n; another comment\n";
+
+    let output = run_oneshot_highlight("ini", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let section = face_at(&output, 2, 2).expect("section header should be highlighted");
+    let key = face_at(&output, 3, 2).expect("key should be highlighted");
+    let value = face_at(&output, 3, 9).expect("value should be highlighted");
+    assert_ne!(section, key, "section header should not use the key face");
+    assert_ne!(key, value, "value should not use the key face");
+
+    // Both line comment styles are comments, distinct from the settings
+    let hash = face_at(&output, 1, 3).expect("`#` comment should be highlighted");
+    let semicolon = face_at(&output, 4, 3).expect("`;` comment should be highlighted");
+    assert_eq!(hash, semicolon, "`#` and `;` comments should share a face");
+    assert_ne!(hash, key, "comment should not use the key face");
+    assert_ne!(hash, value, "comment should not use the value face");
+}
+
+#[test]
+fn nix_indented_string_escapes() {
+    let code = r#"s = ''
+  keep ''' quote ''${not} done
+'';
+t = 1;
+"#;
+
+    let output = run_oneshot_highlight("nix", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Neither ''' nor ''${ ends the indented string
+    let before = face_at(&output, 2, 3).expect("string content should be highlighted");
+    let middle = face_at(&output, 2, 12).expect("string content should be highlighted");
+    let after = face_at(&output, 2, 27).expect("string content should be highlighted");
+    assert_eq!(before, middle, "''' should not end the indented string");
+    assert_eq!(before, after, "''${{ should not start an antiquotation");
+
+    let number = face_at(&output, 4, 5).expect("number should be highlighted");
+    assert_ne!(before, number, "'' should end the indented string");
+}
+
+#[test]
+fn jsonc_comment_marker_in_string() {
+    let code = r#"{
+  "url": "https://example.com", // comment
+}
+"#;
+
+    let output = run_oneshot_highlight("jsonc", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let string = face_at(&output, 2, 11).expect("string should be highlighted");
+    let after_slashes = face_at(&output, 2, 19).expect("string should be highlighted");
+    assert_eq!(
+        string, after_slashes,
+        "// inside a string should stay a string"
+    );
+
+    let comment = face_at(&output, 2, 33).expect("comment should be highlighted");
+    assert_ne!(string, comment, "// after the value should start a comment");
+}
+
+#[test]
+fn proto_field_number_highlighting() {
+    let code = r#"message User {
+  int32 id = 3;
+}
+"#;
+
+    let output = run_oneshot_highlight("proto", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let scalar = face_at(&output, 2, 3).expect("scalar type should be highlighted");
+    let number = face_at(&output, 2, 14).expect("field number should be highlighted");
+    assert_ne!(scalar, number, "field number should not use the type face");
+    assert_ne!(
+        Some(number),
+        face_at(&output, 2, 12),
+        "= should not use the field number face"
+    );
+}
+
+#[test]
+fn typescript_function_call_highlighting() {
+    let code = r#"function greet(name: string) {}
+greet("a");
+obj.method();
+"#;
+
+    let output = run_oneshot_highlight("typescript", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Declaration and call names are both function-scoped; whether they get
+    // different colors is up to the theme's meta.function-call rules
+    let decl = face_at(&output, 1, 10).expect("declared name should be highlighted");
+    let call = face_at(&output, 2, 1).expect("called name should be highlighted");
+    assert_ne!(decl, "default", "declared name should use a function face");
+    assert_ne!(call, "default", "called name should use a function face");
+
+    // Only the method name gets the call face, not the object
+    let method = face_at(&output, 3, 5).expect("method name should be highlighted");
+    assert_eq!(method, call, "method name should use the call face");
+    assert_ne!(
+        Some(method),
+        face_at(&output, 3, 1),
+        "object should not use the call face"
+    );
+}
+
+#[test]
+fn csv_quoted_field_keeps_column() {
+    let code = "a,\"b,c\",d\n";
+
+    let output = run_oneshot_highlight("csv", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // Columns alternate faces, and the comma inside the quoted field doesn't
+    // start a new column
+    let second = face_at(&output, 1, 4).expect("second column should be highlighted");
+    let still_second = face_at(&output, 1, 6).expect("quoted field should be highlighted");
+    assert_eq!(
+        second, still_second,
+        "comma in a quoted field should not start a column"
+    );
+    assert_ne!(
+        face_at(&output, 1, 1),
+        Some(second.clone()),
+        "adjacent columns should use different faces"
+    );
+    assert_ne!(
+        face_at(&output, 1, 9),
+        Some(second),
+        "third column should not use the second column's face"
+    );
+}
+
+#[test]
+fn haskell_nested_block_comment() {
+    let code = r#"{- outer {- inner -} still -}
+x = 1
+"#;
+
+    let output = run_oneshot_highlight("haskell", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // The inner -} closes only the nested comment
+    let outer = face_at(&output, 1, 4).expect("comment should be highlighted");
+    let still = face_at(&output, 1, 22).expect("comment should be highlighted");
+    assert_eq!(outer, still, "nested block comments should balance");
+
+    let number = face_at(&output, 2, 5).expect("number should be highlighted");
+    assert_ne!(outer, number, "the outer -}} should close the comment");
+}
+
+#[test]
+fn git_commit_subject_overflow_highlighting() {
+    let code = r#"Add a subject line that runs well past the fifty character limit and beyond it
+
+Body text explains the change.
+
+# Please enter the commit message for your changes.
+"#;
+
+    let output = run_oneshot_highlight("git-commit", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    // The subject, the part past column 50 and the part past column 72 are
+    // scoped separately
+    let subject = face_at(&output, 1, 1).expect("subject should be highlighted");
+    assert_ne!(
+        Some(subject.clone()),
+        face_at(&output, 1, 60),
+        "text past column 50 should not use the subject face"
+    );
+    assert_ne!(
+        Some(subject),
+        face_at(&output, 1, 77),
+        "text past column 72 should not use the subject face"
+    );
+
+    // Comment lines are distinct from the body
+    let comment = face_at(&output, 5, 3).expect("comment should be highlighted");
+    assert_ne!(
+        Some(comment),
+        face_at(&output, 3, 1),
+        "comment lines should not use the body face"
+    );
+}
+
+#[test]
+fn log_level_highlighting() {
+    let code = "2025-01-15T10:00:00Z INFO [server] retry after ERROR from upstream\n";
+
+    let output = run_oneshot_highlight("log", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let message = face_at(&output, 1, 36);
+    let level = face_at(&output, 1, 22).expect("level should be highlighted");
+    let timestamp = face_at(&output, 1, 1).expect("timestamp should be highlighted");
+    assert_ne!(
+        Some(level),
+        message,
+        "level should not use the message face"
+    );
+    assert_ne!(
+        Some(timestamp),
+        message,
+        "timestamp should not use the message face"
+    );
+
+    // ERROR in the message is a token, it doesn't recolor the rest of the line
+    assert_eq!(
+        message,
+        face_at(&output, 1, 59),
+        "a level word mid-message should not recolor the line"
+    );
+}
+
+#[test]
+fn dockerfile_continued_run_highlighting() {
+    let code = r#"FROM rust:1.88-slim AS builder
+RUN apt-get update \
+    && echo RUN \
+    && true
+"#;
+
+    let output = run_oneshot_highlight("docker", "catppuccin-frappe", code);
+    assert_contains_highlighting(&output);
+    assert_has_ranges(&output);
+
+    let instruction = face_at(&output, 2, 1).expect("RUN instruction should be highlighted");
+    let from = face_at(&output, 1, 1).expect("FROM should be highlighted");
+    assert_eq!(from, instruction, "FROM should use the instruction face");
+    let stage = face_at(&output, 1, 21).expect("AS should be highlighted");
+    assert_eq!(stage, instruction, "AS in FROM ... AS should be a keyword");
+
+    // RUN on a continuation line is shell text, not an instruction
+    assert_ne!(
+        face_at(&output, 3, 13),
+        Some(instruction),
+        "RUN inside a continued command should not use the instruction face"
+    );
+}
+
 #[test]
 fn multiline_string_highlighting() {
     let code = r#"const longString = "This is a very long string that \